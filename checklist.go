@@ -3,6 +3,7 @@ package prompt
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 func getChecked(dst, options reflect.Value) ([]bool, error) {
@@ -10,7 +11,7 @@ func getChecked(dst, options reflect.Value) ([]bool, error) {
 	if dst.Type().Elem() == options.Type().Elem() {
 		for j := 0; j < dst.Len(); j++ {
 			for i := 0; i < len(checked); i++ {
-				if options.Index(i).Equal(dst.Index(j)) {
+				if equalValue(options.Index(i), dst.Index(j)) {
 					checked[i] = true
 					break
 				}
@@ -36,7 +37,11 @@ func getChecked(dst, options reflect.Value) ([]bool, error) {
 	return checked, nil
 }
 
+// Checklist is a list selection prompt that allows to select multiple of the list of possible values. The ioptions must be a slice of options. The idst must be a pointer to a slice of the same type as the options (set the option values) or of integers (set the option indices), or a slice of booleans. The value of idst determines the initially checked values.
+// Users can check an option using Space or Enter, Ctrl+A to check all options matching the query or uncheck them if they are all checked, Ctrl+C to quit, and Ctrl+D or Escape to confirm.
 func Checklist(idst interface{}, label string, ioptions interface{}) error {
+	defer pauseProgress()()
+
 	dst := reflect.ValueOf(idst)
 	options := reflect.ValueOf(ioptions)
 	if dst.Kind() != reflect.Pointer || dst.Elem().Kind() != reflect.Slice {
@@ -58,36 +63,74 @@ func Checklist(idst interface{}, label string, ioptions interface{}) error {
 		optionStrings[i] = fmt.Sprint(options.Index(i).Interface())
 	}
 
-	// set constants
-	selected := 0
-	maxLines := selectMaxLines
-	if _, rows, err := TerminalSize(); err != nil {
-		return err
-	} else if rows-1 < maxLines {
-		maxLines = rows - 1 // keep one for prompt row
-	}
-	scrollOffset := selectScrollOffset
-	withQuery := maxLines < options.Len() || 10 < options.Len()
-	exitEnter := false
-
-	err = terminalList(label, optionStrings, selected, maxLines, scrollOffset, withQuery, exitEnter, func(i, selected int) string {
-		s := "[ ] %v"
-		if checked[i] {
-			s = "[\u00D7] %v"
+	if scriptReader != nil {
+		fmt.Printf("%v: ", label)
+		var line string
+		if line, err = readScriptLine(); err == nil && strings.TrimSpace(line) != "" {
+			for i := range checked {
+				checked[i] = false
+			}
+			for _, answer := range strings.Split(line, ",") {
+				var i int
+				if i, err = scriptChoice(answer, optionStrings); err != nil {
+					break
+				}
+				checked[i] = true
+			}
 		}
-		if i == selected {
-			s = escBold + s + escReset
+		fmt.Printf(escMoveStart + escClearLine)
+	} else if accessible {
+		var choices []int
+		if choices, err = accessibleList(label, optionStrings, true); err == nil && choices != nil {
+			for i := range checked {
+				checked[i] = false
+			}
+			for _, i := range choices {
+				checked[i] = true
+			}
 		}
-		return s
-	}, func(r rune, i int) {
-		if r == ' ' || r == '\n' || r == '\r' {
-			checked[i] = !checked[i]
+	} else {
+		// set constants
+		selected := 0
+		maxLines := selectMaxLines
+		if _, rows, err := TerminalSize(); err != nil {
+			return err
+		} else if rows-2 < maxLines {
+			maxLines = rows - 2 // keep one for prompt row and one for the footer
 		}
-	})
+		scrollOffset := selectScrollOffset
+		withQuery := maxLines < options.Len() || 10 < options.Len()
+		exitEnter := false
+
+		footer := "space: toggle  ctrl+a: all  esc: confirm  ctrl+c: cancel"
+		err = terminalList(label, "", footer, optionStrings, selected, maxLines, scrollOffset, withQuery, exitEnter, func(i, selected int) string {
+			s := "[ ] %v"
+			if checked[i] {
+				s = "[\u00D7] %v"
+			}
+			if i == selected {
+				s = Bold.sprintf("%v", s)
+			}
+			return s
+		}, func(r rune, i int) {
+			if r == ' ' || r == '\n' || r == '\r' {
+				checked[i] = !checked[i]
+			}
+		}, func(visible []int) {
+			// check all visible options, or uncheck them if all are checked
+			all := true
+			for _, i := range visible {
+				all = all && checked[i]
+			}
+			for _, i := range visible {
+				checked[i] = !all
+			}
+		})
+	}
 
 	fmt.Printf("%v: ", label)
 	if err != nil {
-		if err == keyInterrupt {
+		if err == ErrInterrupt {
 			fmt.Printf("^C")
 		}
 		fmt.Printf("\n")
@@ -132,3 +175,26 @@ func Checklist(idst interface{}, label string, ioptions interface{}) error {
 	dst.Set(value)
 	return nil
 }
+
+// SelectMap is a checklist prompt that fills idst with whether each option is selected. Options that are true in idst are initially selected.
+func SelectMap(idst *map[string]bool, label string, options []string) error {
+	if idst == nil {
+		return fmt.Errorf("destination must be a pointer to a variable")
+	}
+
+	checked := make([]bool, len(options))
+	for i, option := range options {
+		checked[i] = (*idst)[option]
+	}
+	if err := Checklist(&checked, label, options); err != nil {
+		return err
+	}
+
+	if *idst == nil {
+		*idst = make(map[string]bool, len(options))
+	}
+	for i, option := range options {
+		(*idst)[option] = checked[i]
+	}
+	return nil
+}
@@ -1,60 +1,921 @@
 package prompt
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"reflect"
+	"sort"
 	"strings"
 )
 
+type formField struct {
+	label    string
+	padded   string // label padded to the width of the other labels
+	value    interface{}
+	input    func() error
+	apply    func() error // apply the default value without prompting
+	cond     func() bool  // condition of the field, nil if unconditional
+	editable bool         // whether the field can be returned to with Escape
+	redacted bool         // whether the value of the field is masked
+	optional bool         // whether the field can be skipped with Escape
+	unset    bool         // whether the field was skipped in the last Send
+	done     bool         // whether the field was completed in the last Send
+	filled   bool         // whether the destination holds an answer, which replaces the default value
+	origin   int          // index of the field, or of the dynamic field that added it
+
+	validation bool        // whether the field is a validation step
+	build      func(*Form) // build function of a dynamic field
+	children   []int       // fields added by the dynamic field
+}
+
 type Form struct {
-	labels []string
-	inputs []func() error
+	fields      []formField
+	order       []int       // indices of the fields in order of appearance, including the fields added by dynamic fields
+	building    bool        // whether fields are added by a dynamic field
+	buildFrom   int         // origin of the fields added by a dynamic field
+	expanding   int         // dynamic field whose fields are added
+	built       []int       // fields added by the dynamic field so far
+	free        []int       // indices of removed fields in increasing order, reused by the fields added by the next dynamic fields
+	last        int         // index of the last added field
+	cond        func() bool // condition of the fields added next
+	labelWidth  int
+	title       string
+	description string
+	progress    bool               // show the number of the field before the label
+	before      func(int, int) int // called before each field with the index of the previous field, returns the number of printed lines
 }
 
 func NewForm() *Form {
 	return &Form{}
 }
 
+// add adds a field with its current value or destination, and the functions that prompt for the value and that apply the default value.
+func (f *Form) add(label string, ival interface{}, editable bool, input, apply func() error) {
+	i := f.next()
+	if i == len(f.fields) {
+		f.fields = append(f.fields, formField{})
+	} else {
+		f.free = f.free[1:]
+	}
+	f.fields[i] = formField{
+		label:    label,
+		value:    ival,
+		input:    input,
+		apply:    apply,
+		cond:     f.cond,
+		editable: editable,
+		origin:   i,
+	}
+	if f.building {
+		f.fields[i].origin = f.buildFrom
+		f.built = append(f.built, i)
+	} else {
+		f.order = append(f.order, i)
+	}
+	f.last = i
+}
+
+// next returns the index of the next added field, which reuses the index of a removed field when added by a dynamic field.
+func (f *Form) next() int {
+	if f.building && 0 < len(f.free) {
+		return f.free[0]
+	}
+	return len(f.fields)
+}
+
 func (f *Form) Print(label string, ival interface{}) {
-	i := len(f.labels)
-	f.labels = append(f.labels, label)
-	f.inputs = append(f.inputs, func() error {
-		fmt.Printf("%v: %v\n", f.labels[i], ival)
+	i := f.next()
+	print := func() error {
+		fmt.Printf("%v: %v\n", f.fields[i].padded, ival)
 		return nil
-	})
+	}
+	f.add(label, ival, false, print, print)
 }
 
 func (f *Form) Prompt(idst interface{}, label string, validators ...Validator) {
-	i := len(f.labels)
-	f.labels = append(f.labels, label)
-	f.inputs = append(f.inputs, func() error {
-		return Prompt(idst, f.labels[i], validators...)
+	i := f.next()
+	idst, popts := unwrapOptions(idst)
+	opts := applyOptions(popts)
+
+	var ideflt interface{}
+	dst := idst
+	if deflt, ok := idst.(defaultValue); ok {
+		dst, ideflt = deflt.idst, deflt.ideflt
+	}
+	f.add(label, dst, true, func() error {
+		if f.fields[i].filled {
+			// show the answer instead of the default value
+			return Prompt(WithOptions(dst, popts...), f.fields[i].padded, validators...)
+		}
+		return Prompt(WithOptions(idst, popts...), f.fields[i].padded, validators...)
+	}, func() error {
+		return f.applyDefault(i, dst, ideflt, append(validators[:len(validators):len(validators)], opts.async...))
+	})
+	f.fields[i].redacted = opts.sensitive
+}
+
+// YesNo adds a yes or no prompt that answers with a single key press, where the current value of the destination is the default answer.
+func (f *Form) YesNo(dst *bool, label string) {
+	i := f.next()
+	f.add(label, dst, true, func() error {
+		answer, err := YesNoKey(f.fields[i].padded, *dst)
+		if err == nil {
+			*dst = answer
+		}
+		return err
+	}, func() error {
+		return f.applyDefault(i, dst, nil, nil)
 	})
 }
 
 func (f *Form) Select(idst interface{}, label string, ioptions interface{}) {
-	i := len(f.labels)
-	f.labels = append(f.labels, label)
-	f.inputs = append(f.inputs, func() error {
-		return Select(idst, f.labels[i], ioptions)
+	f.SelectFunc(idst, label, func() (interface{}, error) {
+		return ioptions, nil
 	})
 }
 
-func (f *Form) Send() error {
+// SelectFunc adds a select prompt whose options are returned by the options function when the field is reached, so that they can depend on the answers of earlier fields. An error returned by the options function is returned as a FieldError by Send.
+func (f *Form) SelectFunc(idst interface{}, label string, options func() (interface{}, error)) {
+	i := f.next()
+	f.add(label, idst, true, func() error {
+		ioptions, err := options()
+		if err != nil {
+			return &FieldError{i, err}
+		}
+		return Select(idst, f.fields[i].padded, ioptions)
+	}, func() error {
+		ioptions, err := options()
+		if err != nil {
+			return &FieldError{i, err}
+		}
+		dst, options := reflect.ValueOf(idst), reflect.ValueOf(ioptions)
+		if dst.Kind() != reflect.Pointer || options.Kind() != reflect.Slice {
+			return fmt.Errorf("destination must be a pointer and options must be a slice")
+		}
+		ok := false
+		if dst.Elem().Type() == options.Type().Elem() {
+			for j := 0; j < options.Len(); j++ {
+				ok = ok || equalValue(options.Index(j), dst.Elem())
+			}
+		} else if _, err := getSelected(dst.Elem(), options); err != nil {
+			return err
+		} else if k := dst.Elem().Kind(); reflect.Int <= k && k <= reflect.Int64 {
+			ok = 0 <= dst.Elem().Int() && dst.Elem().Int() < int64(options.Len())
+		} else {
+			ok = dst.Elem().Uint() < uint64(options.Len())
+		}
+		if !ok {
+			return &FieldError{i, fmt.Errorf("%v: default is not an option", strings.TrimSpace(label))}
+		}
+		fmt.Printf("%v: %v\n", f.fields[i].padded, f.valueString(i))
+		return nil
+	})
+}
+
+// applyDefault sets the destination of a text prompt to its default value if given and the destination was not filled by an answer, Prefill, or ImportJSON, validates the value, and prints the label and value.
+func (f *Form) applyDefault(i int, idst, ideflt interface{}, validators []Validator) error {
+	label := strings.TrimSpace(f.fields[i].label)
+	dst := reflect.ValueOf(idst)
+	if dst.Kind() != reflect.Pointer {
+		return fmt.Errorf("destination must be a pointer to a variable")
+	}
+	val := dst.Elem()
+	if ideflt != nil && !f.fields[i].filled {
+		if v := reflect.ValueOf(ideflt); v.Type().AssignableTo(val.Type()) {
+			val = v
+		} else if ival, err := parseValue(val.Interface(), dst, fmt.Sprint(ideflt)); err != nil {
+			return &FieldError{i, fmt.Errorf("%v: invalid default: %w", label, err)}
+		} else {
+			val = reflect.ValueOf(ival)
+		}
+	}
+	if val.IsZero() && val.Kind() != reflect.Bool && !f.fields[i].optional {
+		return &FieldError{i, fmt.Errorf("%v: missing default", label)}
+	}
+	for _, validator := range validators {
+		if err := validator(val.Interface()); err != nil {
+			return &FieldError{i, fmt.Errorf("%v: %w", label, err)}
+		}
+	}
+	dst.Elem().Set(val)
+	fmt.Printf("%v: %v\n", f.fields[i].padded, f.valueString(i))
+	return nil
+}
+
+// FieldError is an error of a form field, where Field is the index of the field in order of addition starting at zero.
+type FieldError struct {
+	Field int
+	Err   error
+}
+
+func (err *FieldError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *FieldError) Unwrap() error {
+	return err.Err
+}
+
+// ValidateOption is an option for Form.Validate.
+type ValidateOption struct {
+	field int
+}
+
+// Refield re-runs the field with the given index, in order of addition starting at zero, when the validation fails.
+func Refield(field int) ValidateOption {
+	return ValidateOption{field}
+}
+
+// Validate adds a validation step that runs after all previously added fields have been filled, which allows validation across multiple fields. When validation fails the error is printed and the nearest preceding text or select prompt is re-run, or the field given by the Refield option or by the returned FieldError, which must be a text or select prompt. Otherwise Send returns the error.
+func (f *Form) Validate(validator func() error, opts ...ValidateOption) {
+	preceding := f.order
+	if f.building {
+		preceding = append(append([]int{}, f.order[:f.position(f.expanding)+1]...), f.built...)
+	}
+	field := -1
+	for pos := len(preceding) - 1; 0 <= pos; pos-- {
+		if f.fields[preceding[pos]].editable {
+			field = preceding[pos]
+			break
+		}
+	}
+	for _, opt := range opts {
+		field = opt.field
+	}
+	validate := func() error {
+		err := validator()
+		if err == nil {
+			return nil
+		}
+		ferr, ok := err.(*FieldError)
+		if !ok && field == -1 {
+			return err
+		} else if !ok {
+			ferr = &FieldError{field, err}
+		}
+		if ferr.Field < 0 || len(f.fields) <= ferr.Field || !f.fields[ferr.Field].editable {
+			// re-running a print or validation step would fail again
+			return fmt.Errorf("field %d is not a text or select prompt: %w", ferr.Field, ferr.Err)
+		}
+		return ferr
+	}
+	f.add("", nil, false, validate, validate)
+	f.fields[f.last].validation = true
+}
+
+// Dynamic adds fields that are built when the form reaches this point, so that they can depend on the answers of earlier fields, such as a prompt for each of a number of servers. The build function adds fields to the given form as usual, which are shown before the fields that are added after Dynamic. When the form reaches this point again, such as after going back or after changing an earlier answer in Review, the previously built fields are removed and build is called again. The rebuilt fields reuse the lowest indices of the removed fields in order of addition, so that the indices of the fields of a build that adds the same fields are stable, such as for Refield, while the indices of other added fields are not.
+func (f *Form) Dynamic(build func(f *Form)) {
+	i := f.next()
+	expand := func() error {
+		f.expand(i)
+		return nil
+	}
+	f.add("", nil, false, expand, expand)
+	f.fields[i].build = build
+}
+
+// expand removes the fields previously added by the dynamic field and adds the fields of its build function after it, reusing the indices of the removed fields.
+func (f *Form) expand(i int) {
+	f.collapse(i)
+	cond, building, buildFrom, expanding, built := f.cond, f.building, f.buildFrom, f.expanding, f.built
+	f.cond, f.building, f.buildFrom, f.expanding, f.built = nil, true, f.fields[i].origin, i, nil
+	f.fields[i].build(f)
+	fields := f.built
+	f.cond, f.building, f.buildFrom, f.expanding, f.built = cond, building, buildFrom, expanding, built
+
+	pos := f.position(i) + 1
+	f.order = append(f.order[:pos], append(fields, f.order[pos:]...)...)
+	f.fields[i].children = fields
+}
+
+// collapse removes the fields added by a dynamic field from the form, and frees their indices to be reused.
+func (f *Form) collapse(i int) {
+	fields := f.fields[i].children
+	f.fields[i].children = nil
+	for _, field := range fields {
+		if pos := f.position(field); pos != -1 {
+			f.order = append(f.order[:pos], f.order[pos+1:]...)
+		}
+		f.collapse(field)
+		f.fields[field] = formField{}
+		f.free = append(f.free, field)
+	}
+	sort.Ints(f.free)
+}
+
+// position returns the position of a field in the order of appearance, or -1 if it has been removed.
+func (f *Form) position(field int) int {
+	for pos, i := range f.order {
+		if i == field {
+			return pos
+		}
+	}
+	return -1
+}
+
+// Title sets a header that is printed above the first field, with the title in bold and the description dimmed below it and wrapped to the terminal width. The header is printed again when the form is shown again, such as when returning to the first field or in the review.
+func (f *Form) Title(title, description string) {
+	f.title = title
+	f.description = description
+}
+
+// header prints the title and description and returns the number of printed lines.
+func (f *Form) header() int {
+	if f.title == "" && f.description == "" {
+		return 0
+	}
+	title, description := Bold, Dim
+	if accessible {
+		title, description = 0, 0
+	}
 	n := 0
-	for _, label := range f.labels {
-		if n < len(label) {
-			n = len(label)
+	if f.title != "" {
+		fmt.Println(title.sprintf("%v", f.title))
+		n++
+	}
+	if f.description != "" {
+		for _, line := range strings.Split(Wrap(f.description, 0, 0), "\n") {
+			fmt.Println(description.sprintf("%v", line))
+			n++
+		}
+	}
+	return n
+}
+
+// SetLabelWidth sets the width of the labels, instead of the width of the longest label. Longer labels are truncated with an ellipsis. A width of zero restores the default.
+func (f *Form) SetLabelWidth(n int) {
+	f.labelWidth = n
+}
+
+// SetProgress shows the number of the current field and the total number of fields, such as [3/12], before the label of each text and select prompt. Fields that are skipped by their condition are not counted.
+func (f *Form) SetProgress(enable bool) {
+	f.progress = enable
+}
+
+// counter returns the number of the field and the total number of text and select prompts that are currently not skipped, formatted as [3/12].
+func (f *Form) counter(field int) string {
+	n, total := 0, 0
+	for pos, i := range f.order {
+		if f.fields[i].editable && !f.skipped(i) {
+			total++
+			if pos <= f.position(field) {
+				n++
+			}
 		}
 	}
-	for i, label := range f.labels {
-		if len(label) < n {
-			f.labels[i] = strings.Repeat(" ", n-len(label)) + label
+	digits := len(fmt.Sprint(total))
+	if f.fields[field].editable {
+		return fmt.Sprintf("[%*d/%d] ", digits, n, total)
+	}
+	return strings.Repeat(" ", 2*digits+4)
+}
+
+// When sets the condition of the fields added afterwards, which are only shown when cond returns true. The condition is evaluated when the field is reached, so that it can depend on the answers of earlier fields. Skipped fields leave their destination untouched. Call When with nil to add unconditional fields again.
+func (f *Form) When(cond func() bool) {
+	f.cond = cond
+}
+
+// skipped returns true if the field is skipped by its condition.
+func (f *Form) skipped(i int) bool {
+	return f.fields[i].cond != nil && !f.fields[i].cond()
+}
+
+// align pads the labels to equal width, excluding the labels of fields that are currently skipped. Optional fields are suffixed by "(optional)".
+func (f *Form) align() {
+	const suffix = " (optional)"
+	width := func(i int) int {
+		w := stringWidth(f.fields[i].label)
+		if f.fields[i].optional {
+			w += len(suffix)
+		}
+		return w
+	}
+
+	n := f.labelWidth
+	if n <= 0 {
+		for _, i := range f.order {
+			if w := width(i); n < w && !f.skipped(i) {
+				n = w
+			}
+		}
+	}
+	for i := range f.fields {
+		label := f.fields[i].label
+		if f.fields[i].optional && accessible {
+			label += suffix
+		} else if f.fields[i].optional {
+			label += " " + Dim.sprintf("%v", suffix[1:])
+		}
+		if w := width(i); 0 < f.labelWidth && n < w {
+			// truncate to n-1 columns and append an ellipsis
+			rs := []rune(f.fields[i].label)
+			w = stringWidth(f.fields[i].label)
+			for n-1 < w {
+				w -= runeWidth(rs[len(rs)-1])
+				rs = rs[:len(rs)-1]
+			}
+			f.fields[i].padded = string(rs) + strings.Repeat(" ", n-1-w) + "\u2026"
+		} else if w < n {
+			f.fields[i].padded = strings.Repeat(" ", n-w) + label
+		} else {
+			f.fields[i].padded = label
 		}
 	}
-	for _, input := range f.inputs {
-		if err := input(); err != nil {
+}
+
+// Send shows the fields in order of addition. Pressing Escape skips an optional field, or otherwise returns to the previous text or select prompt with its answer as the initial value, or returns ErrEscape when there is no previous field.
+func (f *Form) Send() error {
+	for i := range f.fields {
+		f.collapse(i)
+	}
+	for i := range f.fields {
+		f.fields[i].done = false
+		f.fields[i].unset = false
+	}
+	return f.send()
+}
+
+// Resume continues the last Send, such as after an interrupt or error, and shows only the fields that were not completed. The completed fields and fields set by Prefill keep their values.
+func (f *Form) Resume() error {
+	return f.send()
+}
+
+// SendNonInteractive fills the fields without prompting, such as for unattended runs. Text prompts take the value set by Prefill or ImportJSON, or otherwise their default value given by Default or the current value of the destination, and select prompts take the current value of the destination. The label and value of each field are printed. A FieldError is returned for the first field whose value is missing, is not an option, or does not pass validation. Zero values are missing except for booleans and optional fields.
+func (f *Form) SendNonInteractive() error {
+	for i := range f.fields {
+		f.collapse(i)
+	}
+	for pos := 0; pos < len(f.order); pos++ {
+		i := f.order[pos]
+		f.fields[i].done = false
+		if f.skipped(i) {
+			f.fields[i].unset = true
+			f.collapse(i)
+			continue
+		}
+		f.align()
+		if err := f.fields[i].apply(); err != nil {
 			return err
 		}
+		f.fields[i].done = true
+		f.fields[i].filled = f.fields[i].filled || f.fields[i].editable
 	}
 	return nil
 }
+
+func (f *Form) send() error {
+	defer pauseProgress()()
+
+	prev := []int{}   // index of the previous field
+	rows := []int{}   // number of printed lines of each shown field
+	shown := []bool{} // whether the field was shown in this call
+	last := -1
+	for pos := 0; pos < len(f.order); pos++ {
+		for len(prev) < len(f.fields) {
+			prev, rows, shown = append(prev, -1), append(rows, 0), append(shown, false)
+		}
+
+		i := f.order[pos]
+		if f.fields[i].done {
+			prev[i], rows[i], shown[i] = last, 0, false
+			last = i
+			continue
+		} else if f.skipped(i) {
+			f.fields[i].unset = true
+			f.collapse(i)
+			continue
+		}
+		prev[i], rows[i], shown[i] = last, 0, true
+		f.fields[i].unset = false
+		f.align()
+		if f.progress {
+			f.fields[i].padded = f.counter(i) + f.fields[i].padded
+		}
+		j := last
+		for 0 <= j && !shown[j] {
+			j = prev[j]
+		}
+		if j == -1 {
+			rows[i] += f.header()
+		}
+		if f.before != nil {
+			rows[i] += f.before(j, i)
+		}
+		if err := f.fields[i].input(); err == ErrEscape && f.fields[i].optional {
+			if scriptReader == nil && !accessible {
+				fmt.Printf(escMoveUp+escMoveStart+escClearLine+"%v: %v\n", f.fields[i].padded, Dim.sprintf("(skipped)"))
+			}
+			f.fields[i].unset = true
+			f.fields[i].done = true
+			rows[i]++
+			last = i
+			continue
+		} else if err == ErrEscape {
+			// go back to the previous editable field and erase the lines of the later fields
+			n := rows[i] + 1
+			j := prev[i]
+			for 0 <= j && !f.fields[j].editable {
+				n += rows[j]
+				j = prev[j]
+			}
+			if j < 0 {
+				return err
+			}
+			n += rows[j]
+			fmt.Printf(escMoveUpN+escMoveStart+escClearBelow, n)
+			for _, k := range f.order[f.position(j):pos] {
+				f.fields[k].done = false
+			}
+			pos, last = f.position(j)-1, prev[j]
+			continue
+		} else if err != nil {
+			if ferr, ok := err.(*FieldError); ok && 0 <= f.position(ferr.Field) && f.position(ferr.Field) < pos {
+				fmt.Println((Red | Bold).sprintf("ERROR: %v", ferr))
+				for _, k := range f.order[f.position(ferr.Field):pos] {
+					f.fields[k].done = false
+				}
+				pos = f.position(ferr.Field) - 1
+				last = -1
+				for q := pos; 0 <= q; q-- {
+					if !f.skipped(f.order[q]) {
+						last = f.order[q]
+						break
+					}
+				}
+				continue
+			}
+			return err
+		}
+		if f.fields[i].editable || f.fields[i].label != "" {
+			rows[i]++
+		}
+		f.fields[i].done = true
+		f.fields[i].filled = f.fields[i].filled || f.fields[i].editable
+		last = i
+	}
+	return nil
+}
+
+// redactedValue replaces the values of redacted fields.
+const redactedValue = "********"
+
+// Redact marks the last added field as sensitive, its value is masked in the review and in the answers.
+func (f *Form) Redact() {
+	if 0 < len(f.fields) {
+		f.fields[f.last].redacted = true
+	}
+}
+
+// Optional marks the last added field as optional, its label is followed by "(optional)" and pressing Escape skips the field and leaves its destination untouched.
+func (f *Form) Optional() {
+	if 0 < len(f.fields) {
+		f.fields[f.last].optional = true
+	}
+}
+
+// Unset returns true if the field with the given index, in order of addition starting at zero, was skipped in the last Send, either because it is optional or because of its condition.
+func (f *Form) Unset(field int) bool {
+	return 0 <= field && field < len(f.fields) && f.fields[field].unset
+}
+
+// valueString returns the current value of a field for display.
+func (f *Form) valueString(i int) string {
+	ival := f.fields[i].value
+	if stringer, ok := ival.(interface{ String() string }); ok {
+		return stringer.String()
+	} else if b, ok := ival.(*[]byte); ok {
+		ival = hex.EncodeToString(*b)
+	} else if val := reflect.ValueOf(ival); val.Kind() == reflect.Pointer && !val.IsNil() {
+		ival = val.Elem().Interface()
+	}
+	s := fmt.Sprint(ival)
+	if f.fields[i].redacted && s != "" {
+		s = redactedValue
+	}
+	return s
+}
+
+// Review prints a summary of the labels and current values of the fields that are not skipped, and lets the user select a field to fill in again with its current value as the initial value. The summary is shown again after each change until Confirm is selected. The validation steps after a changed field, and all validation steps when confirming, are run again, and a failing step shows its error and re-runs its field before the summary is shown again.
+func (f *Form) Review() error {
+	defer pauseProgress()()
+
+	for {
+		f.align()
+		fields := []int{}
+		labels := []string{}
+		rows := f.header()
+		for _, i := range f.order {
+			if f.skipped(i) || !f.fields[i].editable && f.fields[i].label == "" {
+				continue
+			}
+			rows++
+			fmt.Printf("%v: %v\n", f.fields[i].padded, f.valueString(i))
+			if f.fields[i].editable {
+				fields = append(fields, i)
+				labels = append(labels, strings.TrimSpace(f.fields[i].label))
+			}
+		}
+		selected := len(labels)
+		if err := Select(&selected, "Change", append(labels, "Confirm")); err != nil {
+			return err
+		}
+		rows++
+
+		from := 0 // position of the first validation step to run
+		if selected < len(fields) {
+			field := fields[selected]
+			if err := f.fields[field].input(); err != nil && err != ErrEscape {
+				return err
+			}
+			rows++
+
+			// rebuild the later dynamic fields and fill in their fields
+			rebuild := false
+			for _, i := range f.order[f.position(field):] {
+				if f.fields[i].build != nil {
+					f.fields[i].done = false
+					rebuild = true
+				}
+			}
+			if rebuild {
+				if err := f.send(); err != nil {
+					return err
+				}
+				rows = 0 // keep the rebuilt fields on screen
+			}
+			from = f.position(field) + 1
+		}
+
+		if err := f.validate(from); err != nil {
+			ferr, ok := err.(*FieldError)
+			if !ok {
+				return err
+			}
+			fmt.Println((Red | Bold).sprintf("ERROR: %v", ferr))
+			if err := f.fields[ferr.Field].input(); err != nil && err != ErrEscape {
+				return err
+			}
+			rows += 2
+		} else if selected == len(fields) {
+			return nil
+		}
+		if scriptReader == nil && !accessible && 0 < rows {
+			// erase the summary, the selection, the fields, and the errors
+			fmt.Printf(escMoveUpN+escMoveStart+escClearBelow, rows)
+		}
+	}
+}
+
+// validate runs the validation steps that are not skipped from the given position in order of appearance, and returns the first error.
+func (f *Form) validate(from int) error {
+	for _, i := range f.order[from:] {
+		if f.fields[i].validation && !f.skipped(i) {
+			if err := f.fields[i].input(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FormWizard is a form that is divided into pages, where each page starts with a breadcrumb of the section names with the current section highlighted.
+type FormWizard struct {
+	*Form
+	pages []int // index of the first field of each page after the first
+	names []string
+}
+
+func NewFormWizard() *FormWizard {
+	return &FormWizard{Form: NewForm()}
+}
+
+// NextPage starts a new page, the fields added afterwards are shown on the new page.
+func (w *FormWizard) NextPage() {
+	w.pages = append(w.pages, len(w.fields))
+}
+
+// SetSectionNames sets the names of the pages in order, which are shown in the breadcrumb.
+func (w *FormWizard) SetSectionNames(names []string) {
+	w.names = names
+}
+
+// page returns the index of the page of a field.
+func (w *FormWizard) page(field int) int {
+	page := 0
+	for page < len(w.pages) && w.pages[page] <= field {
+		page++
+	}
+	return page
+}
+
+// breadcrumb returns the section names separated by '>' with the given page highlighted.
+func (w *FormWizard) breadcrumb(page int) string {
+	items := make([]string, len(w.pages)+1)
+	for i := range items {
+		name := fmt.Sprintf("Step %d", i+1)
+		if i < len(w.names) {
+			name = w.names[i]
+		}
+		if i == page && accessible {
+			name = "[" + name + "]"
+		} else if i == page {
+			name = (Bold | Underline).sprintf("%v", name)
+		} else if !accessible {
+			name = Dim.sprintf("%v", name)
+		}
+		items[i] = name
+	}
+	return strings.Join(items, " > ")
+}
+
+// Send shows the fields page by page, printing the breadcrumb above the first field of each page.
+func (w *FormWizard) Send() error {
+	return w.withBreadcrumbs(w.Form.Send)
+}
+
+// Resume continues the last Send like Form.Resume, printing the breadcrumb above the first shown field of each page.
+func (w *FormWizard) Resume() error {
+	return w.withBreadcrumbs(w.Form.Resume)
+}
+
+func (w *FormWizard) withBreadcrumbs(send func() error) error {
+	w.before = func(prev, i int) int {
+		if page := w.page(w.fields[i].origin); prev == -1 || w.page(w.fields[prev].origin) != page {
+			fmt.Println(w.breadcrumb(page))
+			return 1
+		}
+		return 0
+	}
+	defer func() {
+		w.before = nil
+	}()
+	return send()
+}
+
+// ExportJSON writes a JSON object to w that maps the labels to the current values of the text and select prompts that were not skipped in the last Send, in order of addition. The labels are trimmed and spaces are replaced by underscores. Values are written as strings as if entered by the user, where byte slices are hexadecimal and the values of redacted fields are masked.
+func (f *Form) ExportJSON(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for _, i := range f.answered() {
+		key, err := json.Marshal(formKey(f.fields[i].label))
+		if err != nil {
+			return err
+		}
+		ival := f.answer(i)
+		if b, ok := ival.([]byte); ok {
+			ival = hex.EncodeToString(b)
+		}
+		value, err := json.Marshal(fmt.Sprint(ival))
+		if err != nil {
+			return err
+		}
+		if buf.Len() != 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	out := &bytes.Buffer{}
+	if err := json.Indent(out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// ImportJSON reads a JSON object from r and sets the fields whose label matches a key, such as written by ExportJSON. Fields without a matching key retain their current value, and masked values of redacted fields are ignored.
+func (f *Form) ImportJSON(r io.Reader) error {
+	m := map[string]json.RawMessage{}
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return err
+	}
+	for _, i := range f.order {
+		dst := reflect.ValueOf(f.fields[i].value)
+		if dst.Kind() != reflect.Pointer {
+			continue
+		}
+		key := formKey(f.fields[i].label)
+		raw, ok := m[key]
+		if !ok {
+			continue
+		}
+		var res string
+		if err := json.Unmarshal(raw, &res); err != nil {
+			// use numbers and booleans as is
+			res = string(raw)
+		} else if f.fields[i].redacted && res == redactedValue {
+			continue
+		}
+		ival, err := parseValue(dst.Elem().Interface(), dst, res)
+		if err != nil {
+			return fmt.Errorf("%v: %w", key, err)
+		}
+		dst.Elem().Set(reflect.ValueOf(ival))
+		f.fields[i].filled = true
+	}
+	return nil
+}
+
+// Answers returns the current values of the text and select prompts that were not skipped in the last Send, keyed by their label like ExportJSON. The values have the type of their destination, and the values of redacted fields are masked.
+func (f *Form) Answers() map[string]any {
+	answers := map[string]any{}
+	for _, i := range f.answered() {
+		answers[formKey(f.fields[i].label)] = f.answer(i)
+	}
+	return answers
+}
+
+// answered returns the indices of the text and select prompts that were not skipped in the last Send.
+func (f *Form) answered() []int {
+	fields := []int{}
+	for _, i := range f.order {
+		if f.fields[i].editable && !f.Unset(i) {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+// answer returns the current value of a field.
+func (f *Form) answer(i int) any {
+	if f.fields[i].redacted {
+		return redactedValue
+	} else if val := reflect.ValueOf(f.fields[i].value); val.Kind() == reflect.Pointer {
+		return val.Elem().Interface()
+	}
+	return f.fields[i].value
+}
+
+// MarshalJSON returns the answers as a JSON object in order of addition.
+func (f *Form) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for _, i := range f.answered() {
+		key, err := json.Marshal(formKey(f.fields[i].label))
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.answer(i))
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() != 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Prefill sets the destinations of the fields whose label matches a key, such as returned by Answers, which become the initial values when the form is sent, or are skipped by Resume. Values are converted to the type of the destination, and strings are parsed as if entered by the user. Masked values of redacted fields are ignored.
+func (f *Form) Prefill(answers map[string]any) error {
+	for _, i := range f.order {
+		dst := reflect.ValueOf(f.fields[i].value)
+		if !f.fields[i].editable || dst.Kind() != reflect.Pointer {
+			continue
+		}
+		key := formKey(f.fields[i].label)
+		ival, ok := answers[key]
+		if !ok || ival == nil || f.fields[i].redacted && ival == redactedValue {
+			continue
+		}
+
+		val := reflect.ValueOf(ival)
+		if typ := dst.Elem().Type(); val.Type().AssignableTo(typ) {
+			dst.Elem().Set(val)
+		} else if s, ok := ival.(string); ok {
+			res, err := parseValue(dst.Elem().Interface(), dst, s)
+			if err != nil {
+				return fmt.Errorf("%v: %w", key, err)
+			}
+			dst.Elem().Set(reflect.ValueOf(res))
+		} else if isNumber(val.Kind()) && isNumber(typ.Kind()) {
+			dst.Elem().Set(val.Convert(typ))
+		} else {
+			return fmt.Errorf("%v: cannot use %v as %v", key, val.Type(), typ)
+		}
+		f.fields[i].done = true
+		f.fields[i].filled = true
+	}
+	return nil
+}
+
+// isNumber returns true for integer and floating point kinds.
+func isNumber(kind reflect.Kind) bool {
+	return reflect.Int <= kind && kind <= reflect.Float64
+}
+
+// formKey returns the JSON key of a label.
+func formKey(label string) string {
+	return strings.Join(strings.Fields(label), "_")
+}
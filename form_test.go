@@ -0,0 +1,260 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormDynamicIndices(t *testing.T) {
+	n := 2
+	names := make([]string, 3)
+	f := NewForm()
+	f.Dynamic(func(f *Form) {
+		for j := 0; j < n; j++ {
+			f.Prompt(Default(&names[j], "name"), "Name")
+		}
+	})
+
+	most := 0
+	for _, m := range []int{2, 2, 3, 1, 3} {
+		n = m
+		if most < n {
+			most = n
+		}
+		if err := f.SendNonInteractive(); err != nil {
+			t.Fatal(err)
+		}
+		if len(f.order) != 1+n {
+			t.Fatalf("%d fields shown, expected %d", len(f.order), 1+n)
+		}
+		for j, i := range f.order[1:] {
+			if i != 1+j {
+				t.Fatalf("field %d has index %d, expected %d", j, i, 1+j)
+			}
+		}
+		if len(f.fields) != 1+most {
+			t.Fatalf("%d fields added, expected %d", len(f.fields), 1+most)
+		}
+	}
+}
+
+func TestFormSensitive(t *testing.T) {
+	token, name := "", ""
+	f := NewForm()
+	f.Prompt(WithOptions(Default(&token, "secret"), Sensitive()), "Token")
+	f.Prompt(Default(&name, "name"), "Name")
+	if err := f.SendNonInteractive(); err != nil {
+		t.Fatal(err)
+	} else if token != "secret" {
+		t.Fatalf("token %q, expected the default", token)
+	}
+	if !f.fields[0].redacted || f.fields[1].redacted {
+		t.Fatalf("redacted %v, expected only the sensitive field", []bool{f.fields[0].redacted, f.fields[1].redacted})
+	} else if answers := f.Answers(); answers["Token"] != redactedValue || answers["Name"] != "name" {
+		t.Fatalf("answers %v, expected a masked token", answers)
+	}
+}
+
+func TestFormAlign(t *testing.T) {
+	tests := []struct {
+		other      string // label of another field
+		optional   bool
+		labelWidth int
+		padded     []string // for Name, Город, and 名前
+	}{
+		{"", false, 0, []string{"Name", "Город", "名前"}},
+		{"Postal code", false, 0, []string{"       Name", "      Город", "       名前"}},
+		{"", true, 0, []string{"Name (optional)", "Город (optional)", "名前 (optional)"}},
+		{"Postal code identifier", true, 0, []string{"       Name (optional)", "      Город (optional)", "       名前 (optional)"}},
+		{"", false, 8, []string{"    Name", "   Город", "    名前"}},
+		{"", false, 4, []string{"Name", "Гор…", "名前"}},
+		{"", false, 3, []string{"Na…", "Го…", "名…"}},
+		{"", true, 3, []string{"Na…", "Го…", "名…"}},
+	}
+	plain := strings.NewReplacer(escDim, "", escReset, "")
+	for _, tt := range tests {
+		for j, label := range []string{"Name", "Город", "名前"} {
+			s := ""
+			f := NewForm()
+			f.Prompt(&s, label)
+			if tt.optional {
+				f.Optional()
+			}
+			if tt.other != "" {
+				f.Prompt(&s, tt.other)
+			}
+			f.SetLabelWidth(tt.labelWidth)
+			f.align()
+
+			padded := plain.Replace(f.fields[0].padded)
+			if padded != tt.padded[j] {
+				t.Errorf("%q with %q: padded %q, expected %q", label, tt.other, padded, tt.padded[j])
+			} else if width := stringWidth(f.fields[0].padded); tt.other != "" && width != stringWidth(tt.other) || 0 < tt.labelWidth && width != tt.labelWidth {
+				t.Errorf("%q with %q: width %v", label, tt.other, width)
+			}
+		}
+	}
+}
+
+func TestFormScriptEscape(t *testing.T) {
+	name, age, city := "", 0, ""
+	f := NewForm()
+	f.Prompt(&name, "Name")
+	f.Prompt(&age, "Age")
+	f.Prompt(&city, "City")
+
+	SetScriptReader(strings.NewReader("Alice\n30\n\x1B\n31\nParis\n"))
+	defer SetScriptReader(nil)
+	if err := f.Send(); err != nil {
+		t.Fatal(err)
+	} else if name != "Alice" || age != 31 || city != "Paris" {
+		t.Fatalf("answers %v, %v, %v, expected Alice, 31, Paris", name, age, city)
+	}
+
+	SetScriptReader(strings.NewReader("\x1B\n"))
+	if err := f.Send(); err != ErrEscape {
+		t.Fatalf("error %v, expected ErrEscape at the first field", err)
+	}
+}
+
+func TestFormScriptOptional(t *testing.T) {
+	name, nick := "", "none"
+	f := NewForm()
+	f.Prompt(&nick, "Nickname")
+	f.Optional()
+	f.Prompt(&name, "Name")
+
+	SetScriptReader(strings.NewReader("\x1B\nAlice\n"))
+	defer SetScriptReader(nil)
+	if err := f.Send(); err != nil {
+		t.Fatal(err)
+	} else if nick != "none" || name != "Alice" {
+		t.Fatalf("answers %v, %v, expected none, Alice", nick, name)
+	} else if !f.Unset(0) || f.Unset(1) {
+		t.Fatalf("unset %v, %v, expected only the optional field", f.Unset(0), f.Unset(1))
+	}
+}
+
+func TestFormScriptWhen(t *testing.T) {
+	country, province, zip := "", "", ""
+	f := NewForm()
+	f.Prompt(&country, "Country")
+	f.When(func() bool { return country == "NL" })
+	f.Prompt(&province, "Province")
+	f.When(nil)
+	f.Prompt(&zip, "Zip code")
+
+	SetScriptReader(strings.NewReader("BE\n1000\n"))
+	defer SetScriptReader(nil)
+	if err := f.Send(); err != nil {
+		t.Fatal(err)
+	} else if province != "" || zip != "1000" || !f.Unset(1) {
+		t.Fatalf("answers %q, %q, expected the province to be skipped", province, zip)
+	}
+
+	SetScriptReader(strings.NewReader("NL\nUtrecht\n3500\n"))
+	if err := f.Send(); err != nil {
+		t.Fatal(err)
+	} else if province != "Utrecht" || zip != "3500" || f.Unset(1) {
+		t.Fatalf("answers %q, %q, expected Utrecht, 3500", province, zip)
+	}
+}
+
+func TestFormScriptReview(t *testing.T) {
+	name, age := "", 0
+	f := NewForm()
+	f.Prompt(&name, "Name")
+	f.Prompt(&age, "Age")
+	f.Validate(func() error {
+		if age < 18 {
+			return fmt.Errorf("too young")
+		}
+		return nil
+	})
+
+	SetScriptReader(strings.NewReader("Alice\n30\nAge\n17\n18\nName\nBob\nConfirm\n"))
+	defer SetScriptReader(nil)
+	if err := f.Send(); err != nil {
+		t.Fatal(err)
+	} else if err := f.Review(); err != nil {
+		t.Fatal(err)
+	} else if name != "Bob" || age != 18 {
+		t.Fatalf("answers %v, %v, expected Bob, 18", name, age)
+	}
+}
+
+func TestFormScriptResume(t *testing.T) {
+	name, age, city := "", 0, ""
+	f := NewForm()
+	f.Prompt(&name, "Name")
+	f.Prompt(&age, "Age")
+	f.Prompt(&city, "City")
+
+	SetScriptReader(strings.NewReader("Alice\n"))
+	defer SetScriptReader(nil)
+	if err := f.Send(); err == nil || err.Error() != "script: no more answers" {
+		t.Fatalf("error %v at the end of the script", err)
+	}
+
+	SetScriptReader(strings.NewReader("30\nParis\n"))
+	if err := f.Resume(); err != nil {
+		t.Fatal(err)
+	} else if name != "Alice" || age != 30 || city != "Paris" {
+		t.Fatalf("answers %v, %v, %v, expected Alice, 30, Paris", name, age, city)
+	}
+}
+
+func TestFormJSON(t *testing.T) {
+	type answers struct {
+		name  string
+		age   int
+		token string
+	}
+	form := func(a *answers) *Form {
+		f := NewForm()
+		f.Prompt(&a.name, "Full name")
+		f.Prompt(&a.age, "Age")
+		f.Prompt(WithOptions(&a.token, Sensitive()), "Token")
+		return f
+	}
+
+	src := answers{}
+	f := form(&src)
+	SetScriptReader(strings.NewReader("Alice Smith\n30\nsecret\n"))
+	defer SetScriptReader(nil)
+	if err := f.Send(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.Buffer{}
+	if err := f.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	} else if out := buf.String(); !strings.Contains(out, `"Full_name": "Alice Smith"`) || !strings.Contains(out, `"Token": "`+redactedValue+`"`) {
+		t.Fatalf("JSON %v misses the answers or a masked token", out)
+	}
+
+	imported := answers{token: "kept"}
+	if err := form(&imported).ImportJSON(&buf); err != nil {
+		t.Fatal(err)
+	} else if imported != (answers{"Alice Smith", 30, "kept"}) {
+		t.Fatalf("imported %v, expected the answers with the token kept", imported)
+	}
+
+	prefilled := answers{token: "kept"}
+	g := form(&prefilled)
+	if err := g.Prefill(f.Answers()); err != nil {
+		t.Fatal(err)
+	} else if prefilled != (answers{"Alice Smith", 30, "kept"}) {
+		t.Fatalf("prefilled %v, expected the answers with the token kept", prefilled)
+	}
+
+	// the prefilled fields are skipped by Resume
+	SetScriptReader(strings.NewReader("other\n"))
+	if err := g.Resume(); err != nil {
+		t.Fatal(err)
+	} else if prefilled != (answers{"Alice Smith", 30, "other"}) {
+		t.Fatalf("answers %v after Resume, expected only the token to be asked", prefilled)
+	}
+}
@@ -2,4 +2,8 @@ module github.com/tdewolff/prompt
 
 go 1.18
 
-require github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de
+require (
+	github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de
+	golang.org/x/net v0.15.0
+	golang.org/x/text v0.13.0
+)
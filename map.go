@@ -0,0 +1,146 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PromptMap is a prompt that reads key=value pairs into a map, one pair per line. The existing entries of the map are shown first and are editable in-place, clearing an existing entry removes it from the map. New entries are added until an empty entry is confirmed.
+func PromptMap(idst *map[string]string, label string) error {
+	defer pauseProgress()()
+
+	if idst == nil {
+		return fmt.Errorf("destination must be a pointer to a variable")
+	}
+
+	keys := make([]string, 0, len(*idst))
+	for key := range *idst {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	m := map[string]string{}
+	validator := func(i any) error {
+		entry := i.(string)
+		if entry == "" {
+			return nil
+		}
+		key, _, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return fmt.Errorf("expected key=value")
+		} else if key == "" {
+			return fmt.Errorf("empty key")
+		} else if _, ok := m[key]; ok {
+			return fmt.Errorf("duplicate key '%v'", key)
+		}
+		return nil
+	}
+
+	fmt.Printf("%v:\n", label)
+	for i := 0; ; i++ {
+		entry := ""
+		if i < len(keys) {
+			entry = keys[i] + "=" + (*idst)[keys[i]]
+		}
+		if err := Prompt(&entry, fmt.Sprintf("  %d", i+1), validator); err != nil {
+			return err
+		} else if entry == "" {
+			if i < len(keys) {
+				continue
+			}
+			break
+		}
+		key, val, _ := strings.Cut(entry, "=")
+		m[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	*idst = m
+	return nil
+}
+
+// KeyValues is a prompt that reads key=value pairs into a map, one pair per line, where the key and value are split at the first equal sign and validated separately. The entries are listed above the input line, starting with the existing entries of the map, and pressing Escape removes the last entry. An empty entry finishes the input, after which the entries are summarized on a single line.
+func KeyValues(idst *map[string]string, label string, keyValidators, valueValidators []Validator) error {
+	defer pauseProgress()()
+
+	if idst == nil {
+		return fmt.Errorf("destination must be a pointer to a variable")
+	}
+
+	keys := make([]string, 0, len(*idst))
+	m := make(map[string]string, len(*idst))
+	for key, val := range *idst {
+		keys = append(keys, key)
+		m[key] = val
+	}
+	sort.Strings(keys)
+
+	validator := func(i any) error {
+		entry := i.(string)
+		if entry == "" {
+			return nil
+		}
+		key, val, ok := strings.Cut(entry, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !ok {
+			return fmt.Errorf("expected key=value")
+		} else if key == "" {
+			return fmt.Errorf("empty key")
+		} else if _, ok := m[key]; ok {
+			return fmt.Errorf("duplicate key '%v'", key)
+		}
+		for _, validator := range keyValidators {
+			if err := validator(key); err != nil {
+				return fmt.Errorf("key: %w", err)
+			}
+		}
+		for _, validator := range valueValidators {
+			if err := validator(val); err != nil {
+				return fmt.Errorf("value: %w", err)
+			}
+		}
+		return nil
+	}
+
+	interactive := scriptReader == nil && !accessible
+	fmt.Printf("%v:\n", label)
+	for i, key := range keys {
+		fmt.Printf("  %d: %v=%v\n", i+1, key, m[key])
+	}
+	for {
+		entry := ""
+		if err := Prompt(&entry, fmt.Sprintf("  %d", len(keys)+1), validator); err == ErrEscape && 0 < len(keys) {
+			delete(m, keys[len(keys)-1])
+			keys = keys[:len(keys)-1]
+			if interactive {
+				// erase the last entry and the input line
+				fmt.Printf(escMoveUpN+escMoveStart+escClearBelow, 2)
+			}
+			continue
+		} else if err != nil {
+			return err
+		} else if entry == "" {
+			break
+		}
+		key, val, _ := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		m[key] = strings.TrimSpace(val)
+		keys = append(keys, key)
+	}
+
+	if interactive {
+		// replace the label, entries, and input line by the summary
+		fmt.Printf(escMoveUpN+escMoveStart+escClearBelow, len(keys)+2)
+	}
+	if len(keys) == 0 {
+		fmt.Printf("%v: %v\n", label, Dim.sprintf("(none)"))
+	} else {
+		entries := make([]string, len(keys))
+		for i, key := range keys {
+			entries[i] = key + "=" + m[key]
+		}
+		fmt.Printf("%v: %v\n", label, strings.Join(entries, ", "))
+	}
+	*idst = m
+	return nil
+}
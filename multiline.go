@@ -0,0 +1,236 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MultilinePrompt is a text prompt for multiple lines of text, which are joined by newlines into idst. The value of idst determines the initial text. All validators receive the full text and must be satisfied, otherwise an error is printed and the text should be corrected.
+// Each line is editable independently. Users can use Left, Right, Up, and Down to move around; Home and End to move to the start and end of the line; Backspace and Delete to delete a character or join lines; Enter to add a new line; Ctrl+C and Escape to quit; and Ctrl+D on an empty last line to confirm the text.
+func MultilinePrompt(idst *string, label string, validators ...Validator) error {
+	defer pauseProgress()()
+
+	if idst == nil {
+		return fmt.Errorf("destination must be a pointer to a variable")
+	}
+
+	var text string
+	var err error
+	if scriptReader != nil || accessible {
+		if text, err = readMultiline(label); err != nil {
+			return err
+		}
+		for _, validator := range validators {
+			if err := validator(text); err != nil {
+				fmt.Println((Red | Bold).sprintf("ERROR: %v", err))
+				return err
+			}
+		}
+	} else if text, err = editMultiline(label, *idst, validators); err != nil {
+		return err
+	}
+	*idst = text
+	return nil
+}
+
+// readMultiline reads lines from the script or standard input until an empty line, which is used in script and accessible mode.
+func readMultiline(label string) (string, error) {
+	fmt.Printf("%v (end with an empty line):\n", label)
+	var input *bufio.Reader
+	if scriptReader == nil {
+		input = bufio.NewReader(os.Stdin)
+	}
+
+	lines := []string{}
+	for {
+		var line string
+		var err error
+		if input == nil {
+			line, err = readScriptLine()
+			fmt.Println(line)
+		} else if line, err = input.ReadString('\n'); err != nil && line == "" {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if err != nil || line == "" {
+			if err != nil && input == nil && len(lines) == 0 {
+				return "", err
+			}
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// editMultiline draws and edits the lines of text in the terminal.
+func editMultiline(label, text string, validators []Validator) (string, error) {
+	lines := [][]rune{}
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, []rune(line))
+	}
+	row, col := len(lines)-1, len(lines[len(lines)-1])
+	msg := ""
+
+	const gutter = 7 // width of the line numbers
+	cursorRow := 0   // row of the cursor relative to the label
+	drawn := 0       // number of rows drawn below the label
+	render := func() {
+		var sb strings.Builder
+		if 0 < cursorRow {
+			fmt.Fprintf(&sb, escMoveUpN, cursorRow)
+		}
+		n := "1 line"
+		if len(lines) != 1 {
+			n = fmt.Sprintf("%d lines", len(lines))
+		}
+		fmt.Fprintf(&sb, escMoveStart+escClearLine+"%v (%v):", label, n)
+		rows := len(lines)
+		for i, line := range lines {
+			fmt.Fprintf(&sb, "\n"+escMoveStart+escClearLine+"%5d  %v", i+1, string(line))
+		}
+		if msg != "" {
+			fmt.Fprintf(&sb, "\n"+escMoveStart+escClearLine+"%v", (Red|Bold).sprintf("ERROR: %v", msg))
+			rows++
+		}
+		for i := rows; i < drawn; i++ {
+			sb.WriteString("\n" + escClearLine)
+		}
+		if n := Max(rows, drawn) - (row + 1); 0 < n {
+			fmt.Fprintf(&sb, escMoveUpN, n)
+		}
+		fmt.Fprintf(&sb, escMoveToCol, gutter+col+1)
+		fmt.Print(sb.String())
+		cursorRow = row + 1
+		drawn = rows
+	}
+
+	// make raw input
+	restore, err := MakeRawTerminal(false)
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+	defer func() {
+		// move below the text and clear the line count indicator and error
+		if 0 < cursorRow {
+			fmt.Printf(escMoveUpN, cursorRow)
+		}
+		fmt.Printf(escMoveStart+escClearLine+"%v:", label)
+		for _, line := range lines {
+			fmt.Printf("\n"+escMoveStart+escClearLine+"  %v", string(line))
+		}
+		for i := len(lines); i < drawn; i++ {
+			fmt.Printf("\n" + escClearLine)
+		}
+		if n := drawn - len(lines); 0 < n {
+			fmt.Printf(escMoveUpN, n)
+		}
+		fmt.Printf("\n")
+	}()
+
+	input := bufio.NewReader(os.Stdin)
+	for {
+		render()
+
+		var r rune
+		if r, _, err = input.ReadRune(); err != nil {
+			return "", err
+		}
+
+		if r == '\x03' { // interrupt
+			return "", ErrInterrupt
+		} else if r == '\x04' { // Ctrl+D
+			if row == len(lines)-1 && len(lines[row]) == 0 {
+				text := make([]string, len(lines)-1)
+				for i := range text {
+					text[i] = string(lines[i])
+				}
+				msg = ""
+				for _, validator := range validators {
+					if verr := validator(strings.Join(text, "\n")); verr != nil {
+						msg = verr.Error()
+						break
+					}
+				}
+				if msg == "" {
+					lines = lines[:len(lines)-1]
+					return strings.Join(text, "\n"), nil
+				}
+			}
+		} else if r == '\r' || r == '\n' { // new line
+			tail := append([]rune{}, lines[row][col:]...)
+			lines[row] = lines[row][:col]
+			lines = append(lines[:row+1], append([][]rune{tail}, lines[row+1:]...)...)
+			row, col = row+1, 0
+		} else if r == '\x7F' { // backspace
+			if col != 0 {
+				lines[row] = append(lines[row][:col-1], lines[row][col:]...)
+				col--
+			} else if row != 0 {
+				col = len(lines[row-1])
+				lines[row-1] = append(lines[row-1], lines[row]...)
+				lines = append(lines[:row], lines[row+1:]...)
+				row--
+			}
+		} else if r == '\x1B' { // escape
+			if input.Buffered() == 0 {
+				return "", ErrEscape
+			} else if r, _, err = input.ReadRune(); err != nil {
+				return "", err
+			} else if r == '[' { // CSI
+				if input.Buffered() == 0 {
+					// ignore
+				} else if r, _, err = input.ReadRune(); err != nil {
+					return "", err
+				} else if r == 'A' { // up
+					if row != 0 {
+						row--
+						col = Min(col, len(lines[row]))
+					}
+				} else if r == 'B' { // down
+					if row != len(lines)-1 {
+						row++
+						col = Min(col, len(lines[row]))
+					}
+				} else if r == 'D' { // left
+					if col != 0 {
+						col--
+					} else if row != 0 {
+						row--
+						col = len(lines[row])
+					}
+				} else if r == 'C' { // right
+					if col != len(lines[row]) {
+						col++
+					} else if row != len(lines)-1 {
+						row++
+						col = 0
+					}
+				} else if r == 'H' { // home
+					col = 0
+				} else if r == 'F' { // end
+					col = len(lines[row])
+				} else if r == '3' {
+					if input.Buffered() == 0 {
+						// ignore
+					} else if r, _, err = input.ReadRune(); err != nil {
+						return "", err
+					} else if r == '~' { // delete
+						if col != len(lines[row]) {
+							lines[row] = append(lines[row][:col], lines[row][col+1:]...)
+						} else if row != len(lines)-1 {
+							lines[row] = append(lines[row], lines[row+1]...)
+							lines = append(lines[:row+1], lines[row+2:]...)
+						}
+					}
+				}
+			}
+		} else if ' ' <= r {
+			lines[row] = append(lines[row][:col], append([]rune{r}, lines[row][col:]...)...)
+			col++
+		}
+	}
+}
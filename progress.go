@@ -1,15 +1,18 @@
 package prompt
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"math"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
 )
 
@@ -37,85 +40,563 @@ func DefaultProgressStyle(b []byte, f float64) {
 	b[len(b)-1] = ']'
 }
 
+// ProgressStyleFunc appends a progress bar of the given width in columns to the buffer for a fraction between 0 and 1, and returns the extended buffer. The bar may contain escape sequences that do not take up columns. The fraction is NaN when the progress is indeterminate, in which case the frame counter can be used for animation. Use WithStyleFunc to draw a progress bar with it.
+type ProgressStyleFunc func(dst []byte, width int, f float64, frame int) []byte
+
+// Append appends a progress bar of the given width drawn by the style, so that a ProgressStyle can be used as a ProgressStyleFunc. A nil style draws DefaultProgressStyle. Indeterminate progress is animated for every style by a group of # bouncing back and forth between the first and last byte of the bar, use WithStyleFunc to draw indeterminate progress differently.
+func (style ProgressStyle) Append(dst []byte, width int, f float64, frame int) []byte {
+	if width < 1 {
+		return dst
+	}
+	start := len(dst)
+	dst = append(dst, make([]byte, width)...)
+	b := dst[start:]
+	if style == nil {
+		style = DefaultProgressStyle
+	}
+
+	style(b, f)
+	if 3 <= len(b) && math.IsNaN(f) {
+		// bounce group of # back and forth
+		n := len(b) - 2
+		w := Min(3, n)
+		pos := 0
+		if period := 2 * (n - w); 0 < period {
+			pos = frame % period
+			if n-w < pos {
+				pos = period - pos
+			}
+		}
+		for i := 1 + pos; i < 1+pos+w; i++ {
+			b[i] = '#'
+		}
+	}
+	return dst
+}
+
+// GradientProgressStyle renders the filled part of the progress bar in a color that is interpolated between the from and to RGB colors by the fraction. When the terminal does not support true color, the to color is approximated by one of the basic terminal colors. When the NO_COLOR environment variable is set, the default style is used.
+func GradientProgressStyle(from, to [3]uint8) ProgressStyleFunc {
+	plain := ProgressStyle(DefaultProgressStyle).Append
+	if os.Getenv("NO_COLOR") != "" {
+		return plain
+	}
+	colorterm := os.Getenv("COLORTERM")
+	trueColor := colorterm == "truecolor" || colorterm == "24bit"
+	return func(dst []byte, width int, f float64, frame int) []byte {
+		if width < 3 || math.IsNaN(f) {
+			return plain(dst, width, f, frame)
+		}
+		f = math.Max(0.0, math.Min(1.0, f))
+		pos := int(f*float64(width-2) + 0.5)
+
+		dst = append(dst, '[')
+		if trueColor {
+			var c [3]uint8
+			for i := 0; i < 3; i++ {
+				c[i] = uint8(float64(from[i]) + f*(float64(to[i])-float64(from[i])) + 0.5)
+			}
+			dst = fmt.Appendf(dst, "\x1B[38;2;%d;%d;%dm", c[0], c[1], c[2])
+		} else {
+			code := 30
+			for i := 0; i < 3; i++ {
+				if 127 < to[i] {
+					code += 1 << i
+				}
+			}
+			dst = fmt.Appendf(dst, "\x1B[%dm", code)
+		}
+		for i := 0; i < pos; i++ {
+			dst = append(dst, '#')
+		}
+		dst = append(dst, escReset...)
+		for i := pos; i < width-2; i++ {
+			dst = append(dst, '-')
+		}
+		return append(dst, ']')
+	}
+}
+
 type Progress struct {
 	prefix, suffix []byte
-	style          ProgressStyle
+	style          ProgressStyleFunc
 	buf            []byte
+	frame          int
+	f              float64
+	persist        bool
+	paused         bool
+	ownLine        bool
+	bitRate        bool
+	rateLimit      float64
+	stopMsg        func() string
+	ctx            context.Context
+	cancelMsg      string
+	w              io.Writer
+	mu             sync.Mutex
+
+	// plain log mode
+	plain       bool
+	logInterval time.Duration
+	logTime     time.Time
+	logF        float64
+	logged      bool
 
 	active atomic.Bool
 	c      chan os.Signal
 	wg     sync.WaitGroup
 }
 
-func NewProgress(prefix, suffix string, style ProgressStyle) *Progress {
-	return &Progress{
+// ProgressOption is an option for progress bars.
+type ProgressOption func(*Progress)
+
+// WithWriter sets the writer of the progress bar, which is os.Stdout by default. The progress bar is only drawn when the writer is a terminal.
+func WithWriter(w io.Writer) ProgressOption {
+	return func(p *Progress) {
+		p.w = w
+	}
+}
+
+// WithStyleFunc draws the progress bar with the given style, such as GradientProgressStyle, instead of the ProgressStyle of the constructor.
+func WithStyleFunc(style ProgressStyleFunc) ProgressOption {
+	return func(p *Progress) {
+		p.style = style
+	}
+}
+
+// WithPersistOnStop sets whether the final progress bar remains printed after Stop, otherwise it is erased.
+func WithPersistOnStop(persist bool) ProgressOption {
+	return func(p *Progress) {
+		p.persist = persist
+	}
+}
+
+// WithOwnLine draws the progress bar on its own line below the current output, instead of on the current line.
+func WithOwnLine() ProgressOption {
+	return func(p *Progress) {
+		p.ownLine = true
+	}
+}
+
+// WithBitRate shows transfer rates in bits per second (kb/s, Mb/s, ...) with factors of 1000, instead of bytes per second.
+func WithBitRate() ProgressOption {
+	return func(p *Progress) {
+		p.bitRate = true
+	}
+}
+
+// WithRateLimit shows the target or limit of the transfer rate in bytes per second next to the rate, such as "42.1 Mb/s of 100.0 Mb/s".
+func WithRateLimit(limit float64) ProgressOption {
+	return func(p *Progress) {
+		p.rateLimit = limit
+	}
+}
+
+// WithPlainLog forces plain log mode, which is used by default when the writer is not a terminal. Instead of drawing a progress bar, plain lines are written periodically.
+func WithPlainLog() ProgressOption {
+	return func(p *Progress) {
+		p.plain = true
+	}
+}
+
+// WithLogInterval sets the interval between lines in plain log mode, which is five seconds by default. A line is also written for every 10% of progress.
+func WithLogInterval(interval time.Duration) ProgressOption {
+	return func(p *Progress) {
+		p.logInterval = interval
+	}
+}
+
+// WithStopMessage sets a function that returns a summary line, which replaces the progress bar when it stops.
+func WithStopMessage(msg func() string) ProgressOption {
+	return func(p *Progress) {
+		p.stopMsg = msg
+	}
+}
+
+// WithContext stops the progress bar when the context is cancelled and replaces it by the cancellation message. Readers and writers of transfer progress bars return the context's error afterwards.
+func WithContext(ctx context.Context) ProgressOption {
+	return func(p *Progress) {
+		p.ctx = ctx
+	}
+}
+
+// WithCancelMessage sets the line that replaces the progress bar when its context is cancelled, which is the prefix followed by "cancelled" by default.
+func WithCancelMessage(msg string) ProgressOption {
+	return func(p *Progress) {
+		p.cancelMsg = msg
+	}
+}
+
+var progressLogInterval = 5 * time.Second
+var progressAnimationInterval = 200 * time.Millisecond
+
+func NewProgress(prefix, suffix string, style ProgressStyle, opts ...ProgressOption) *Progress {
+	p := &Progress{
 		prefix: []byte(prefix),
 		suffix: []byte(suffix),
-		style:  style,
+		style:  style.Append,
 	}
+	p.apply(opts)
+	return p
 }
 
+func (p *Progress) apply(opts []ProgressOption) {
+	for _, opt := range opts {
+		opt(p)
+	}
+}
+
+func (p *Progress) writer() io.Writer {
+	if p.w == nil {
+		return os.Stdout
+	}
+	return p.w
+}
+
+// terminalWidth returns the width of the terminal of the writer, or false if it is not a terminal.
+func (p *Progress) terminalWidth() (int, bool) {
+	_, w, ok := p.terminalSize()
+	return w, ok
+}
+
+// terminalSize returns the number of rows and columns of the terminal of the writer, or false if it is not a terminal.
+func (p *Progress) terminalSize() (int, int, bool) {
+	if f, ok := p.writer().(*os.File); ok {
+		if h, w, err := terminalSize(f.Fd()); err == nil {
+			return h, w, true
+		}
+	}
+	return 0, 0, false
+}
+
+// isPlain returns true for plain log mode.
+func (p *Progress) isPlain() bool {
+	_, ok := p.terminalWidth()
+	return p.plain || !ok || accessible
+}
+
+// printf writes to the writer only if it is a terminal and not in plain log mode.
+func (p *Progress) printf(format string, args ...interface{}) {
+	if !p.isPlain() {
+		fmt.Fprintf(p.writer(), format, args...)
+	}
+}
+
+// log writes a plain line with the current progress, which must be called with the lock held.
+func (p *Progress) log(f float64) {
+	fmt.Fprintf(p.writer(), "%s%s\n", p.prefix, p.suffix)
+	p.logTime = time.Now()
+	p.logF = f
+	p.logged = true
+}
+
+// logDue returns whether a line is due in plain log mode for the given time and fraction of the last line, which is after the log interval or at every 10% step.
+func (p *Progress) logDue(logTime time.Time, logF, f float64) bool {
+	interval := p.logInterval
+	if interval == 0 {
+		interval = progressLogInterval
+	}
+	return logTime.IsZero() || interval <= time.Since(logTime) || !math.IsNaN(f) && math.Floor(logF*10.0) < math.Floor(f*10.0)
+}
+
+// Start starts the progress bar, which is stopped when the context given by WithContext is cancelled.
 func (p *Progress) Start() {
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p.StartContext(ctx)
+}
+
+// StartContext starts the progress bar, which is stopped when the context is cancelled. A stopped progress bar can be started again.
+func (p *Progress) StartContext(ctx context.Context) {
 	if !p.active.CompareAndSwap(false, true) {
 		return
 	}
+	p.mu.Lock()
+	p.ctx = ctx
+	p.frame = 0
+	p.paused = false
+	p.logged = false
+	p.logTime = time.Time{}
+	p.logF = 0.0
+	p.mu.Unlock()
 
-	p.c = make(chan os.Signal, 1)
-	signal.Notify(p.c, os.Interrupt)
+	setActiveProgress(p)
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	p.c = c
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
 
-		interrupt := false
-		for _ = range p.c {
-			interrupt = true
-			break
-		}
-		if interrupt {
-			p.stop()
-			syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+		// animate indeterminate progress bars even when there is no progress
+		ticker := time.NewTicker(progressAnimationInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case _, interrupt := <-c:
+				if interrupt {
+					p.stop()
+					if !p.ownLine {
+						p.printf("\n")
+					}
+					raiseInterrupt()
+				}
+				return
+			case <-ctx.Done():
+				if p.stop() {
+					p.cancel()
+				}
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				f := p.f
+				p.mu.Unlock()
+				if math.IsNaN(f) && !p.isPlain() {
+					p.Print(f)
+				}
+			}
 		}
 	}()
 
-	fmt.Println()
+	if p.ownLine {
+		p.printf("\n")
+	}
 }
 
 func (p *Progress) stop() bool {
 	if !p.active.CompareAndSwap(true, false) {
 		return false
 	}
+	unsetActiveProgress(p)
 	signal.Stop(p.c)
 	return true
 }
 
+// Finish stops the progress bar and replaces it by a success marker and the prefix if err is nil, or otherwise by a failure marker, the prefix, and the error.
+func (p *Progress) Finish(err error) {
+	ok := err == nil
+	msg := strings.TrimSpace(string(p.prefix))
+	if !ok {
+		msg += ": " + err.Error()
+	}
+	if p.stop() {
+		close(p.c)
+		p.wg.Wait()
+		p.finish(func() string {
+			return statusMarker(ok, !p.isPlain()) + " " + msg
+		})
+	}
+}
+
+// home returns the escape sequence that moves the cursor to the start of the progress bar's line.
+func (p *Progress) home() string {
+	if p.ownLine {
+		return escMoveUp + escMoveStart
+	}
+	return escMoveStart
+}
+
+// eol returns what follows the progress bar, which stays on the current line unless it has its own line.
+func (p *Progress) eol() string {
+	if p.ownLine {
+		return "\n"
+	}
+	return ""
+}
+
+// cancel replaces the progress bar by the cancellation message.
+func (p *Progress) cancel() {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	msg := p.cancelMsg
+	if msg == "" {
+		msg = string(p.prefix) + "cancelled"
+	}
+	if p.isPlain() {
+		fmt.Fprintln(p.writer(), msg)
+	} else if p.paused {
+		fmt.Fprintf(p.writer(), "%s\n", msg)
+	} else {
+		fmt.Fprintf(p.writer(), p.home()+escClearLine+"%s\n", msg)
+	}
+	p.paused = false
+}
+
+// err returns the error of the context when it is cancelled.
+func (p *Progress) err() error {
+	if p.ctx != nil {
+		return p.ctx.Err()
+	}
+	return nil
+}
+
+// Stop stops the progress bar and replaces it by the stop message if set. Otherwise, the final progress bar is redrawn if it should persist or erased.
 func (p *Progress) Stop() {
 	if p.stop() {
 		close(p.c)
 		p.wg.Wait()
+		p.finish(nil)
+	}
+}
+
+// finish writes the final line of a stopped progress bar, which is replaced by the given stop message or otherwise by the stop message of the progress bar.
+func (p *Progress) finish(stopMsg func() string) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if stopMsg == nil {
+		stopMsg = p.stopMsg
+	}
+	clear := p.home() + escClearLine
+	if p.paused {
+		// progress bar was already erased
+		clear = ""
+		p.paused = false
+	}
+	if p.isPlain() {
+		if stopMsg != nil {
+			fmt.Fprintln(p.writer(), stopMsg())
+		} else if !p.logged {
+			p.log(p.logF)
+		}
+	} else if stopMsg != nil {
+		fmt.Fprintf(p.writer(), clear+"%s\n", stopMsg())
+	} else if p.persist {
+		w, _ := p.terminalWidth()
+		p.buf = renderBar(p.buf[:0], p.prefix, p.suffix, p.style, w, p.f, p.frame)
+		fmt.Fprintf(p.writer(), clear+"%s\n", p.buf)
+	} else {
+		fmt.Fprint(p.writer(), clear)
 	}
 }
 
 func (p *Progress) Print(f float64) {
+	p.printSuffix(f, nil)
+}
+
+// printSuffix sets the suffix unless it is nil and prints the progress bar, so that the suffix is replaced under the lock.
+func (p *Progress) printSuffix(f float64, suffix []byte) {
 	if !p.active.Load() {
+		if suffix != nil {
+			p.mu.Lock()
+			p.suffix = append(p.suffix[:0], suffix...)
+			p.mu.Unlock()
+		}
 		return
 	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	_, w, _ := TerminalSize()
-	if w != len(p.buf) {
-		p.buf = make([]byte, w)
+	if suffix != nil {
+		p.suffix = append(p.suffix[:0], suffix...)
+	}
+	p.f = f
+	w, ok := p.terminalWidth()
+	if p.paused {
+		return
+	} else if accessible {
+		// announce every 25%
+		p.logged = false
+		if !math.IsNaN(f) && math.Floor(p.logF*4.0) < math.Floor(f*4.0) {
+			fmt.Fprintf(p.writer(), "%s%.0f%%\n", p.prefix, math.Floor(f*4.0)*25.0)
+			p.logF = f
+			p.logged = true
+		}
+		return
+	} else if p.plain || !ok {
+		if p.logDue(p.logTime, p.logF, f) {
+			p.log(f)
+		} else {
+			p.logged = false
+		}
+		return
 	}
+	p.buf = renderBar(p.buf[:0], p.prefix, p.suffix, p.style, w, f, p.frame)
+	p.frame++
 
-	copy(p.buf, p.prefix)
-	if len(p.prefix)+len(p.suffix) < w {
-		copy(p.buf[w-len(p.suffix):], p.suffix)
+	fmt.Fprintf(p.writer(), p.home()+escClearLine+"%s"+p.eol(), p.buf)
+}
+
+// renderBar appends a line of the given width with the prefix, progress bar, and suffix. When the width is too small for the prefix and suffix, they are truncated and the bar is omitted.
+func renderBar(dst, prefix, suffix []byte, style ProgressStyleFunc, w int, f float64, frame int) []byte {
+	dst = append(dst, prefix[:Min(len(prefix), w)]...)
+	if len(prefix)+len(suffix) < w {
+		dst = style(dst, w-len(prefix)-len(suffix), f, frame)
+		dst = append(dst, suffix...)
+	} else if len(prefix) < w {
+		dst = append(dst, suffix[:w-len(prefix)]...)
 	}
-	if len(p.prefix)+len(p.suffix) < len(p.buf) {
-		p.style(p.buf[len(p.prefix):w-len(p.suffix)], f)
+	return dst
+}
+
+// clampLine truncates a line without escape sequences to the given width.
+func clampLine(line []byte, w int) []byte {
+	for i := range string(line) {
+		if w == 0 {
+			return line[:i]
+		}
+		w--
 	}
+	return line
+}
+
+// Pause erases the progress bar so that the caller can write output freely until Resume is called. Progress updates while paused are not drawn.
+func (p *Progress) Pause() {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	fmt.Printf(escMoveStart + escMoveUp)
-	os.Stdout.Write(p.buf)
-	fmt.Printf("\n")
+	if p.active.Load() && !p.paused {
+		p.paused = true
+		p.printf(p.home() + escClearLine)
+	}
+}
+
+// Resume redraws the progress bar after Pause below any output written in the meantime.
+func (p *Progress) Resume() {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		p.paused = false
+		if w, ok := p.terminalWidth(); ok && p.active.Load() {
+			p.buf = renderBar(p.buf[:0], p.prefix, p.suffix, p.style, w, p.f, p.frame)
+			p.printf("%s"+p.eol(), p.buf)
+		}
+	}
+}
+
+// Log prints a formatted line above the progress bar and redraws the progress bar.
+func (p *Progress) Log(format string, args ...any) {
+	p.PrintLine(fmt.Sprintf(format, args...))
+}
+
+// PrintLine prints a line above the progress bar and redraws the progress bar.
+func (p *Progress) PrintLine(msg string) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	p.printLine(msg)
+}
+
+func (p *Progress) printLine(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isPlain() || !p.active.Load() || p.paused {
+		fmt.Fprintln(p.writer(), msg)
+		return
+	}
+	fmt.Fprintf(p.writer(), p.home()+escClearLine+"%v\n%s"+p.eol(), msg, p.buf)
 }
 
 type Number interface {
@@ -125,180 +606,1167 @@ type Number interface {
 type PercentProgress[T Number] struct {
 	Progress
 	value, maximum T
+
+	// valueMu serializes updates so that the suffix of the latest value is printed last, which an atomic value cannot guarantee
+	valueMu sync.Mutex
 }
 
-func NewPercentProgress[T Number](prefix string, maximum T, style ProgressStyle) *PercentProgress[T] {
-	suffix := make([]byte, 5)
-	suffix[0] = ' '
-	suffix[4] = '%'
-	return &PercentProgress[T]{
+func NewPercentProgress[T Number](prefix string, maximum T, style ProgressStyle, opts ...ProgressOption) *PercentProgress[T] {
+	p := &PercentProgress[T]{
 		Progress: Progress{
 			prefix: []byte(prefix),
-			suffix: suffix,
-			style:  style,
+			suffix: []byte("   0%"),
+			style:  style.Append,
 		},
 		maximum: maximum,
 	}
+	p.apply(opts)
+	return p
 }
 
+// update prints the progress bar, which must be called with the value lock held. The percentage is clipped to [0,100] and is unknown for a zero maximum.
 func (p *PercentProgress[T]) update() {
-	f := float64(p.value) / float64(p.maximum)
-	fmt.Appendf(p.suffix[:1:5], "%3.0f", f*100.0)
-	p.Print(f)
+	f := math.NaN()
+	if p.maximum != 0 {
+		f = math.Max(0.0, math.Min(1.0, float64(p.value)/float64(p.maximum)))
+	}
+	suffix := []byte("   ?%")
+	if !math.IsNaN(f) {
+		suffix = fmt.Appendf(nil, " %3.0f%%", f*100.0)
+	}
+	p.printSuffix(f, suffix)
 }
 
+// Add adds to the value, it is safe for concurrent use.
 func (p *PercentProgress[T]) Add(value T) {
+	p.valueMu.Lock()
+	defer p.valueMu.Unlock()
 	p.value += value
 	p.update()
 }
 
+// Set sets the value, it is safe for concurrent use.
 func (p *PercentProgress[T]) Set(value T) {
+	p.valueMu.Lock()
+	defer p.valueMu.Unlock()
 	p.value = value
 	p.update()
 }
 
-type DownloadProgress struct {
+// Reset sets the value to zero, such as before starting the progress bar again.
+func (p *PercentProgress[T]) Reset() {
+	p.Set(0)
+}
+
+// TimerProgress is a progress bar that fills over a fixed duration, showing the remaining time.
+type TimerProgress struct {
 	Progress
-	value int64
-	resp  *http.Response
+	d    time.Duration
+	done chan struct{}
+	once sync.Once
+	loop sync.WaitGroup
+}
+
+func NewTimerProgress(prefix string, d time.Duration, style ProgressStyle, opts ...ProgressOption) *TimerProgress {
+	p := &TimerProgress{
+		Progress: Progress{
+			prefix: []byte(prefix),
+			style:  style.Append,
+		},
+		d:    d,
+		done: make(chan struct{}),
+	}
+	p.apply(opts)
+	return p
+}
+
+var timerProgressInterval = 100 * time.Millisecond
+
+// Start starts the progress bar and its timer.
+func (p *TimerProgress) Start() {
+	if p.active.Load() {
+		return
+	}
+	p.loop.Wait()
+	select {
+	case <-p.done:
+		// restart after the previous run
+		p.done = make(chan struct{})
+		p.once = sync.Once{}
+	default:
+	}
+	p.Progress.Start()
+
+	t := time.Now()
+	p.update(0)
+	p.loop.Add(1)
+	go func() {
+		defer p.loop.Done()
+		ticker := time.NewTicker(timerProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				elapsed := time.Since(t)
+				p.update(elapsed)
+				if p.d <= elapsed {
+					p.once.Do(func() { close(p.done) })
+					p.Progress.Stop()
+					return
+				}
+			case <-p.ctx.Done():
+				p.once.Do(func() { close(p.done) })
+				return
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+func (p *TimerProgress) update(elapsed time.Duration) {
+	remaining := p.d - elapsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	remaining = (remaining + time.Second - 1).Truncate(time.Second)
+	f := 1.0
+	if 0 < p.d {
+		f = math.Min(1.0, float64(elapsed)/float64(p.d))
+	}
+	p.printSuffix(f, fmt.Appendf(nil, " %v left", remaining))
+}
+
+// Wait blocks after Start until the duration elapses, the progress bar is stopped, or its context is cancelled.
+func (p *TimerProgress) Wait() {
+	<-p.done
+	p.loop.Wait()
+}
+
+// Stop stops the timer and the progress bar early.
+func (p *TimerProgress) Stop() {
+	p.once.Do(func() { close(p.done) })
+	p.loop.Wait()
+	p.Progress.Stop()
+}
+
+type StepProgress struct {
+	Progress
+	step, total int
+	label       string
+}
+
+func NewStepProgress(prefix string, total int, style ProgressStyle, opts ...ProgressOption) *StepProgress {
+	p := &StepProgress{
+		Progress: Progress{
+			prefix: []byte(prefix),
+			style:  style.Append,
+		},
+		total: total,
+	}
+	p.apply(opts)
+	return p
+}
+
+var stepProgressMinBar = 12 // minimum width of the bar, the step label is truncated to fit
+
+func (p *StepProgress) update() {
+	if p.total < p.step {
+		p.total = p.step
+	}
+	suffix := fmt.Appendf(nil, " %d/%d", p.step, p.total)
+	if p.label != "" {
+		label := []rune(p.label)
+		if w, ok := p.terminalWidth(); ok {
+			n := w - len(p.prefix) - len(suffix) - 1 - stepProgressMinBar
+			if n < len(label) {
+				if n < 4 {
+					label = nil
+				} else {
+					label = append(label[:n-3:n-3], []rune("...")...)
+				}
+			}
+		}
+		if 0 < len(label) {
+			suffix = fmt.Appendf(suffix, " %s", string(label))
+		}
+	}
+
+	f := 1.0
+	if 0 < p.total {
+		f = float64(p.step) / float64(p.total)
+	}
+	p.printSuffix(f, suffix)
+}
+
+// Next advances to the next step with the given label.
+func (p *StepProgress) Next(label string) {
+	p.step++
+	p.label = label
+	p.update()
+}
+
+// Reset sets the step to zero, such as before starting the progress bar again.
+func (p *StepProgress) Reset() {
+	p.step = 0
+	p.label = ""
+	p.update()
+}
+
+// SetTotal sets the total number of steps, which may change while running.
+func (p *StepProgress) SetTotal(n int) {
+	p.total = n
+	p.update()
+}
+
+var rateWindowDuration = 5 * time.Second // window over which rates are averaged
+
+type rateSample struct {
 	t     time.Time
+	value int64
 }
 
-func NewDownloadProgress(prefix string, resp *http.Response, style ProgressStyle) *DownloadProgress {
-	p := &DownloadProgress{
+// rateWindow computes the rate of change of a value averaged over a sliding window of time.
+type rateWindow struct {
+	samples []rateSample
+}
+
+func newRateWindow(t time.Time) rateWindow {
+	return rateWindow{[]rateSample{{t, 0}}}
+}
+
+// add adds a sample and returns the rate per second over the window.
+func (w *rateWindow) add(t time.Time, value int64) float64 {
+	w.samples = append(w.samples, rateSample{t, value})
+	for 2 < len(w.samples) && rateWindowDuration < t.Sub(w.samples[1].t) {
+		w.samples = w.samples[1:]
+	}
+	if dt := t.Sub(w.samples[0].t); 0 < dt {
+		return float64(value-w.samples[0].value) / dt.Seconds()
+	}
+	return 0.0
+}
+
+type SpeedProgress struct {
+	Progress
+	value int64
+	unit  string
+	rate  rateWindow
+}
+
+func NewSpeedProgress(prefix string, unit string, style ProgressStyle, opts ...ProgressOption) *SpeedProgress {
+	p := &SpeedProgress{
 		Progress: Progress{
 			prefix: []byte(prefix),
-			style:  style,
+			style:  style.Append,
 		},
-		resp: resp,
-		t:    time.Now(),
+		unit: unit,
+		rate: newRateWindow(time.Now()),
 	}
+	p.apply(opts)
+	return p
+}
+
+// Reset sets the value to zero and restarts the rate, such as before starting the progress bar again.
+func (p *SpeedProgress) Reset() {
+	p.value = 0
+	p.rate = newRateWindow(time.Now())
+	p.update()
+}
+
+func (p *SpeedProgress) update() {
+	rate := p.rate.add(time.Now(), p.value)
+	p.printSuffix(math.NaN(), fmt.Appendf(nil, " %.1f %s/s", rate, p.unit))
+}
+
+func (p *SpeedProgress) Add(n int64) {
+	p.value += n
+	p.update()
+}
+
+// transferProgress is a progress bar for transferring bytes, showing the size, rate, and percentage.
+type transferProgress struct {
+	Progress
+	value  int64
+	offset int64 // bytes transferred before, such as for resumed downloads
+	size   int64
+	t      time.Time
+	rate   rateWindow
+}
+
+func (p *transferProgress) init(prefix string, size int64, style ProgressStyle, opts []ProgressOption) {
+	p.prefix = []byte(prefix)
+	p.style = style.Append
+	p.size = size
+	p.t = time.Now()
+	p.rate = newRateWindow(p.t)
+	p.apply(opts)
 	p.Start()
 	p.update()
-	return p
 }
 
-func (p *DownloadProgress) update() {
-	var f float64
-	dt := time.Since(p.t)
+func (p *transferProgress) update() {
+	p.print(p.rate.add(time.Now(), p.value-p.offset))
+}
 
+func (p *transferProgress) print(rate float64) {
+	var f float64
+	var suffix []byte
 	size, sizeUnit := formatBytes(p.value)
 	sizeStr := fmt.Sprintf("%3.1f %s", size, sizeUnit)
-	rate, rateUnit := formatBytes(int64(float64(p.value)/dt.Seconds() + 0.5))
-	rateStr := fmt.Sprintf("%3.1f %s/s", rate, rateUnit)
+	rateStr := p.formatRate(rate)
 
-	if p.resp.ContentLength <= 0 {
+	if p.size <= 0 {
 		f = math.NaN()
-		p.suffix = fmt.Appendf(p.suffix[:0], " %8s, %10s,   ?%%", sizeStr, rateStr)
+		suffix = fmt.Appendf(nil, " %9s, %*s,   ?%%", sizeStr, p.rateWidth(), rateStr)
 	} else {
-		f = float64(p.value) / float64(p.resp.ContentLength)
-		p.suffix = fmt.Appendf(p.suffix[:0], " %8s, %10s, %3.0f%%", sizeStr, rateStr, f*100.0)
+		f = float64(p.value) / float64(p.size)
+		sizeStr += " / " + formatSize(p.size)
+		suffix = fmt.Appendf(nil, " %21s, %*s, %3.0f%%", sizeStr, p.rateWidth(), rateStr, f*100.0)
+	}
+	p.printSuffix(f, suffix)
+}
+
+// Stop stops the progress bar and shows the average rate over the whole transfer.
+func (p *transferProgress) Stop() {
+	if p.active.Load() {
+		if dt := time.Since(p.t); 0 < dt {
+			p.print(float64(p.value-p.offset) / dt.Seconds())
+		}
 	}
-	p.Print(f)
+	p.Progress.Stop()
+}
+
+// Reset sets the number of transferred bytes to zero and restarts the rate, such as before starting the progress bar again.
+func (p *transferProgress) Reset() {
+	p.value, p.offset = 0, 0
 	p.t = time.Now()
+	p.rate = newRateWindow(p.t)
+	p.update()
 }
 
-func (p *DownloadProgress) Add(value int64) {
+// SetTotal sets the total number of bytes when it becomes known, or zero if unknown. The progress bar is indeterminate while the total is unknown.
+func (p *transferProgress) SetTotal(total int64) {
+	p.size = total
+	p.update()
+}
+
+// SetOffset sets the number of bytes that were transferred before, such as for resumed downloads. The offset counts towards the progress but not towards the rate.
+func (p *transferProgress) SetOffset(n int64) {
+	p.value += n - p.offset
+	p.offset = n
+	p.update()
+}
+
+func (p *transferProgress) Add(value int64) {
 	p.value += value
 	p.update()
 }
 
-func (p *DownloadProgress) Set(value int64) {
+func (p *transferProgress) Set(value int64) {
 	p.value = value
 	p.update()
 }
 
-func (p *DownloadProgress) read(n int, err error) {
+// transfer adds the number of transferred bytes and stops on error or when done.
+func (p *transferProgress) transfer(n int, err error) {
 	p.Add(int64(n))
-	if err != nil || 0 < p.resp.ContentLength && p.resp.ContentLength <= p.value {
+	if err != nil || 0 < p.size && p.size <= p.value {
 		p.Stop()
 	}
 }
 
-func (p *DownloadProgress) Read(b []byte) (int, error) {
-	n, err := p.resp.Body.Read(b)
-	p.read(n, err)
+type readerProgress struct {
+	transferProgress
+	r io.Reader
+}
+
+// NewReaderProgress returns a reader that shows the progress of reading from r, where total is the total number of bytes or zero if unknown. The progress bar stops at EOF or Close.
+func NewReaderProgress(prefix string, r io.Reader, total int64, style ProgressStyle, opts ...ProgressOption) io.ReadCloser {
+	p := &readerProgress{r: r}
+	p.init(prefix, total, style, opts)
+	return p
+}
+
+func (p *readerProgress) Read(b []byte) (int, error) {
+	if err := p.err(); err != nil {
+		return 0, err
+	}
+	n, err := p.r.Read(b)
+	p.transfer(n, err)
 	return n, err
 }
 
-func (p *DownloadProgress) Close() error {
-	err := p.resp.Body.Close()
+// Close stops the progress bar and closes the underlying reader if it is an io.Closer.
+func (p *readerProgress) Close() error {
+	var err error
+	if closer, ok := p.r.(io.Closer); ok {
+		err = closer.Close()
+	}
 	p.Stop()
 	return err
 }
 
-func formatBytes(n int64) (float64, string) {
-	units := []string{"GB", "MB", "kB", "B"}
-	factors := []int64{1000000000, 1000000, 1000, 1}
-	for i, factor := range factors {
-		f := float64(n) / float64(factor)
-		if v, _ := math.Modf(f); 0 < v {
-			return f, units[i]
+type writerProgress struct {
+	transferProgress
+	w io.Writer
+}
+
+// NewWriterProgress returns a writer that shows the progress of writing to w, where total is the total number of bytes or zero if unknown. The progress bar stops when total bytes are written or at Close.
+func NewWriterProgress(prefix string, w io.Writer, total int64, style ProgressStyle, opts ...ProgressOption) io.WriteCloser {
+	p := &writerProgress{w: w}
+	p.init(prefix, total, style, opts)
+	return p
+}
+
+func (p *writerProgress) Write(b []byte) (int, error) {
+	if err := p.err(); err != nil {
+		return 0, err
+	}
+	n, err := p.w.Write(b)
+	p.transfer(n, err)
+	return n, err
+}
+
+// Close stops the progress bar and closes the underlying writer if it is an io.Closer.
+func (p *writerProgress) Close() error {
+	var err error
+	if closer, ok := p.w.(io.Closer); ok {
+		err = closer.Close()
+	}
+	p.Stop()
+	return err
+}
+
+type DownloadProgress struct {
+	readerProgress
+	resp *http.Response
+}
+
+func NewDownloadProgress(prefix string, resp *http.Response, style ProgressStyle, opts ...ProgressOption) *DownloadProgress {
+	p := &DownloadProgress{
+		readerProgress: readerProgress{r: resp.Body},
+		resp:           resp,
+	}
+	size := contentLength(resp)
+	offset, total, ok := contentRange(resp)
+	if ok && 0 < total {
+		size = total
+	}
+	p.init(prefix, size, style, opts)
+	if offset != 0 {
+		p.SetOffset(offset)
+	}
+	return p
+}
+
+// SetOffset sets the number of bytes that were downloaded before when resuming a download, which is derived from the Content-Range header by default. If the total size is unknown, it is the offset plus the content length of a partial response.
+func (p *DownloadProgress) SetOffset(n int64) {
+	if _, total, _ := contentRange(p.resp); total <= 0 && p.resp.StatusCode == http.StatusPartialContent {
+		if length := contentLength(p.resp); 0 < length {
+			p.size = n + length
 		}
 	}
-	return 0.0, "0"
+	p.transferProgress.SetOffset(n)
+}
+
+// contentRange returns the first byte position and the total size from the Content-Range header of a partial response. The total is -1 if unknown.
+func contentRange(resp *http.Response) (int64, int64, bool) {
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, -1, false
+	}
+	var start, end, total int64
+	h := resp.Header.Get("Content-Range")
+	if n, _ := fmt.Sscanf(h, "bytes %d-%d/%d", &start, &end, &total); n == 3 {
+		return start, total, true
+	} else if n == 2 {
+		return start, -1, true
+	}
+	return 0, -1, false
+}
+
+// contentLength returns the response's content length, falling back to the Content-Length header which may be set for redirected responses. It returns -1 if unknown.
+func contentLength(resp *http.Response) int64 {
+	if 0 < resp.ContentLength {
+		return resp.ContentLength
+	} else if n, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil && 0 < n {
+		return n
+	}
+	return -1
 }
 
+// UploadProgress shows the progress of reading an upload from r, such as a request body, where totalSize is the total number of bytes or zero if unknown. The progress bar stops at EOF or Close.
+type UploadProgress struct {
+	readerProgress
+}
+
+func NewUploadProgress(prefix string, totalSize int64, r io.Reader, style ProgressStyle, opts ...ProgressOption) *UploadProgress {
+	p := &UploadProgress{readerProgress{r: r}}
+	p.init(prefix, totalSize, style, opts)
+	return p
+}
+
+var binaryUnits = false
+
+// SetBinaryUnits sets whether byte sizes and rates are shown in binary units (KiB, MiB, ...) with factors of 1024, instead of decimal units (kB, MB, ...) with factors of 1000.
+func SetBinaryUnits(enable bool) {
+	binaryUnits = enable
+}
+
+// formatBytes returns the number of bytes scaled to a unit such that it is below 1000 or 1024.
+func formatBytes(n int64) (float64, string) {
+	base := 1000.0
+	units := []string{"B", "kB", "MB", "GB", "TB", "PB"}
+	if binaryUnits {
+		base = 1024.0
+		units = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	}
+
+	f := float64(n)
+	i := 0
+	for base <= math.Abs(f) && i+1 < len(units) {
+		f /= base
+		i++
+	}
+	return f, units[i]
+}
+
+var multiProgressInterval = 100 * time.Millisecond // interval between repaints
+
+// MultiDownloadProgress shows the progress of multiple concurrent downloads and their total. Items update atomic counters and a single goroutine repaints all progress bars periodically.
 type MultiDownloadProgress struct {
 	items []*MultiDownloadProgressItem
-	style ProgressStyle
-	mu    sync.Mutex
+	total *Progress // total line, also holds the style, writer, and options
+	t     time.Time
+	lines int // number of lines currently drawn
+	frame int
+
+	// accumulated values of removed items
+	removedValue, removedSize int64
+
+	running bool
+	paused  bool
+	quit    chan struct{}
+	c       chan os.Signal
+	wg      sync.WaitGroup
+	mu      sync.Mutex
 }
 
 type MultiDownloadProgressItem struct {
-	download *DownloadProgress
-	parent   *MultiDownloadProgress
-	idx      int
+	prefix string
+	r      io.Reader
+	size   int64
+	value  atomic.Int64
+	done   atomic.Bool
+	t      time.Time
+	dt     atomic.Int64          // duration until done
+	err    atomic.Pointer[error] // error that ended the stream
+	rate   rateWindow            // only used by the renderer
+	logged bool                  // completion logged in plain mode
+
+	// time and fraction of the last progress line in plain mode
+	logTime time.Time
+	logF    float64
 }
 
-func (p *MultiDownloadProgressItem) Read(b []byte) (int, error) {
-	n, err := p.download.resp.Body.Read(b)
+func (item *MultiDownloadProgressItem) finish() {
+	if item.done.CompareAndSwap(false, true) {
+		item.dt.Store(int64(time.Since(item.t)))
+	}
+}
 
-	p.parent.mu.Lock()
-	pos := len(p.parent.items) - p.idx - 1
-	if 0 < pos {
-		fmt.Printf(escMoveUpN, pos)
+// status returns a line with a success or failure marker for a completed item.
+func (item *MultiDownloadProgressItem) status(color bool) []byte {
+	if err := item.err.Load(); err != nil {
+		return fmt.Appendf(nil, "%s%s %v", item.prefix, statusMarker(false, color), *err)
 	}
-	p.download.read(n, err)
-	if 0 < pos {
-		fmt.Printf(escMoveDownN, pos)
+	return fmt.Appendf(nil, "%s%s %v in %v", item.prefix, statusMarker(true, color), formatSize(item.value.Load()), time.Duration(item.dt.Load()).Round(100*time.Millisecond))
+}
+
+func (item *MultiDownloadProgressItem) Read(b []byte) (int, error) {
+	n, err := item.r.Read(b)
+	value := item.value.Add(int64(n))
+	if err != nil && err != io.EOF {
+		item.err.CompareAndSwap(nil, &err)
+	}
+	if err != nil || 0 < item.size && item.size <= value {
+		item.finish()
 	}
-	p.parent.mu.Unlock()
 	return n, err
 }
 
-func (p *MultiDownloadProgressItem) Close() error {
-	p.parent.mu.Lock()
-	err := p.download.Close()
-	p.parent.mu.Unlock()
+// Close marks the item as done and closes the underlying reader if it is an io.Closer.
+func (item *MultiDownloadProgressItem) Close() error {
+	var err error
+	if closer, ok := item.r.(io.Closer); ok {
+		err = closer.Close()
+	}
+	item.finish()
 	return err
 }
 
-func NewMultiDownloadProgress(style ProgressStyle) *MultiDownloadProgress {
-	return &MultiDownloadProgress{
-		style: style,
+func NewMultiDownloadProgress(style ProgressStyle, opts ...ProgressOption) *MultiDownloadProgress {
+	p := &MultiDownloadProgress{
+		total: &Progress{
+			prefix: []byte("Total "),
+			style:  style.Append,
+		},
 	}
+	p.total.apply(opts)
+	return p
 }
 
+// Add adds a download of an HTTP response.
 func (p *MultiDownloadProgress) Add(prefix string, resp *http.Response) io.ReadCloser {
-	p.mu.Lock()
+	return p.AddReader(prefix, resp.Body, contentLength(resp))
+}
 
-	idx := len(p.items)
+// AddReader adds a reader, where total is the total number of bytes or zero if unknown.
+func (p *MultiDownloadProgress) AddReader(prefix string, r io.Reader, total int64) io.ReadCloser {
 	item := &MultiDownloadProgressItem{
-		download: NewDownloadProgress(prefix, resp, p.style),
-		parent:   p,
-		idx:      idx,
+		prefix: prefix,
+		r:      r,
+		size:   total,
+		t:      time.Now(),
 	}
-	p.items = append(p.items, item)
+	item.rate = newRateWindow(item.t)
 
+	p.mu.Lock()
+	p.items = append(p.items, item)
+	if !p.running {
+		p.running = true
+		p.t = item.t
+		p.lines = 0
+		p.paused = false
+		p.quit = make(chan struct{})
+		p.c = make(chan os.Signal, 1)
+		signal.Notify(p.c, os.Interrupt)
+		setActiveProgress(p)
+		p.wg.Add(1)
+		go p.run(p.quit, p.c)
+	}
 	p.mu.Unlock()
 	return item
 }
 
-func (p *MultiDownloadProgress) Stop() {
+// RemoveCompleted removes all completed items from the display, their sizes still count towards the total.
+func (p *MultiDownloadProgress) RemoveCompleted() {
 	p.mu.Lock()
+	items := p.items[:0]
 	for _, item := range p.items {
-		item.download.Stop()
+		if item.done.Load() {
+			p.removedValue += item.value.Load()
+			if item.size <= 0 || p.removedSize < 0 {
+				p.removedSize = -1
+			} else {
+				p.removedSize += item.size
+			}
+		} else {
+			items = append(items, item)
+		}
 	}
+	p.items = items
+	p.mu.Unlock()
+}
+
+func (p *MultiDownloadProgress) run(quit chan struct{}, c chan os.Signal) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(multiProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.lock()
+			if p.render() {
+				p.stop()
+				p.unlock()
+				return
+			}
+			p.unlock()
+		case <-quit:
+			p.lock()
+			p.render()
+			p.stop()
+			p.unlock()
+			return
+		case <-c:
+			p.lock()
+			p.render()
+			p.stop()
+			p.unlock()
+			raiseInterrupt()
+			return
+		}
+	}
+}
+
+// lock locks the output and the progress bars, in that order.
+func (p *MultiDownloadProgress) lock() {
+	outputMu.Lock()
+	p.mu.Lock()
+}
+
+func (p *MultiDownloadProgress) unlock() {
 	p.mu.Unlock()
+	outputMu.Unlock()
+}
+
+// stop stops the renderer, which must be called with the lock held.
+func (p *MultiDownloadProgress) stop() {
+	if p.running {
+		p.running = false
+		signal.Stop(p.c)
+		unsetActiveProgress(p)
+	}
+}
+
+// Pause erases the progress bars so that the caller can write output freely until Resume is called. Progress updates while paused are not drawn.
+func (p *MultiDownloadProgress) Pause() {
+	p.lock()
+	defer p.unlock()
+	if p.running && !p.paused {
+		p.paused = true
+		p.erase()
+	}
+}
+
+// Resume redraws the progress bars after Pause below any output written in the meantime, or the final progress bars if they were stopped while paused.
+func (p *MultiDownloadProgress) Resume() {
+	p.lock()
+	defer p.unlock()
+	if p.paused {
+		p.paused = false
+		p.render()
+	}
+}
+
+// printLine prints a line above the progress bars, which must be called with the output lock held.
+func (p *MultiDownloadProgress) printLine(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.total.isPlain() || !p.running || p.paused {
+		fmt.Fprintln(p.total.writer(), msg)
+		return
+	}
+	p.erase()
+	fmt.Fprintln(p.total.writer(), msg)
+	p.render()
+}
+
+// erase erases the drawn progress bars, which must be called with the locks held.
+func (p *MultiDownloadProgress) erase() {
+	if 0 < p.lines && !p.total.isPlain() {
+		fmt.Fprintf(p.total.writer(), escMoveUpN+escMoveStart+escClearBelow, p.lines)
+	}
+	p.lines = 0
+}
+
+// render repaints all lines and returns true when all items are done, which must be called with the locks held. Nothing is repainted while paused. In plain log mode, the lines of the items and the total are logged periodically and at every 10% step.
+func (p *MultiDownloadProgress) render() bool {
+	now := time.Now()
+	plain := p.total.isPlain()
+	rows, width, _ := p.total.terminalSize()
+
+	// gather totals
+	value, size := p.removedValue, p.removedSize
+	completed := 0
+	for _, item := range p.items {
+		value += item.value.Load()
+		if item.size <= 0 || size < 0 {
+			size = -1
+		} else {
+			size += item.size
+		}
+		if item.done.Load() {
+			completed++
+		}
+	}
+	done := completed == len(p.items)
+	if p.paused {
+		return done
+	}
+
+	// collapse completed items and truncate when there are more items than rows
+	maxItems := Max(1, rows-2) // keep rows for total and cursor
+	collapse := !plain && maxItems < len(p.items)
+	more := 0
+	lines := [][]byte{}
+	if collapse && 0 < completed {
+		lines = append(lines, clampLine([]byte(fmt.Sprintf("\u2713 %d completed", completed)), width))
+	}
+	for _, item := range p.items {
+		v := item.value.Load()
+		if item.done.Load() {
+			if plain && !item.logged {
+				fmt.Fprintf(p.total.writer(), "%s\n", item.status(false))
+				item.logged = true
+			}
+			if plain || collapse {
+				continue
+			}
+		} else if collapse && maxItems-1 <= len(lines) {
+			more++
+			continue
+		}
+
+		var line []byte
+		if item.done.Load() {
+			line = clampLine(item.status(false), width)
+			line = bytes.Replace(line, []byte(statusMarker(item.err.Load() == nil, false)), []byte(statusMarker(item.err.Load() == nil, true)), 1)
+		} else {
+			rateStr := p.total.formatRate(item.rate.add(now, v))
+			f := math.NaN()
+			var suffix []byte
+			if item.size <= 0 {
+				suffix = fmt.Appendf(suffix, " %9s, %*s,   ?%%", formatSize(v), p.total.rateWidth(), rateStr)
+			} else {
+				f = float64(v) / float64(item.size)
+				suffix = fmt.Appendf(suffix, " %9s, %*s, %3.0f%%", formatSize(v), p.total.rateWidth(), rateStr, f*100.0)
+			}
+			if plain {
+				if p.total.logDue(item.logTime, item.logF, f) {
+					fmt.Fprintf(p.total.writer(), "%s%s\n", item.prefix, suffix)
+					item.logTime, item.logF = now, f
+				}
+				continue
+			}
+			line = renderBar(line, []byte(item.prefix), suffix, p.total.style, width, f, p.frame)
+		}
+		lines = append(lines, line)
+	}
+	if 0 < more {
+		lines = append(lines, clampLine([]byte(fmt.Sprintf("... and %d more", more)), width))
+	}
+	p.frame++
+
+	// total line
+	if done {
+		dt := now.Sub(p.t).Round(100 * time.Millisecond)
+		p.total.suffix = fmt.Appendf(p.total.suffix[:0], " Done: %v in %v", formatSize(value), dt)
+		if plain {
+			fmt.Fprintf(p.total.writer(), "%s%s\n", p.total.prefix, p.total.suffix)
+			return true
+		}
+		lines = append(lines, renderBar(nil, p.total.prefix, p.total.suffix, p.total.style, width, 1.0, p.frame))
+	} else {
+		f := math.NaN()
+		if size <= 0 {
+			p.total.suffix = fmt.Appendf(p.total.suffix[:0], " %9s,   ?%%", formatSize(value))
+		} else {
+			f = float64(value) / float64(size)
+			p.total.suffix = fmt.Appendf(p.total.suffix[:0], " %9s, %3.0f%%", formatSize(value), f*100.0)
+		}
+		if plain {
+			// log periodically and at every 10% step
+			if p.total.logDue(p.total.logTime, p.total.logF, f) {
+				p.total.log(f)
+			}
+			return false
+		}
+		lines = append(lines, renderBar(nil, p.total.prefix, p.total.suffix, p.total.style, width, f, p.frame))
+	}
+
+	// repaint block of lines, the cursor is below the block
+	buf := []byte{}
+	if 0 < p.lines {
+		buf = fmt.Appendf(buf, escMoveUpN, p.lines)
+	}
+	for _, line := range lines {
+		buf = append(buf, escMoveStart+escClearLine...)
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+	if len(lines) < p.lines {
+		for i := len(lines); i < p.lines; i++ {
+			buf = append(buf, escClearLine+"\n"...)
+		}
+		buf = fmt.Appendf(buf, escMoveUpN, p.lines-len(lines))
+	}
+	p.total.writer().Write(buf)
+	p.lines = len(lines)
+	return done
+}
+
+// formatBits returns the number of bits scaled to a decimal unit such that it is below 1000.
+func formatBits(n int64) (float64, string) {
+	units := []string{"b", "kb", "Mb", "Gb", "Tb", "Pb"}
+
+	f := float64(n)
+	i := 0
+	for 1000.0 <= math.Abs(f) && i+1 < len(units) {
+		f /= 1000.0
+		i++
+	}
+	return f, units[i]
+}
+
+// formatRate formats a rate in bytes per second, or in bits per second if enabled, followed by the rate limit if set.
+func (p *Progress) formatRate(rate float64) string {
+	format := func(rate float64) string {
+		if p.bitRate {
+			v, unit := formatBits(int64(rate*8.0 + 0.5))
+			return fmt.Sprintf("%3.1f %s/s", v, unit)
+		}
+		v, unit := formatBytes(int64(rate + 0.5))
+		return fmt.Sprintf("%3.1f %s/s", v, unit)
+	}
+	if 0 < p.rateLimit {
+		return format(rate) + " of " + format(p.rateLimit)
+	}
+	return format(rate)
+}
+
+// rateWidth returns the column width of formatted rates.
+func (p *Progress) rateWidth() int {
+	if 0 < p.rateLimit {
+		return 26
+	}
+	return 11
+}
+
+// formatSize formats a number of bytes with its unit.
+func formatSize(n int64) string {
+	v, unit := formatBytes(n)
+	return fmt.Sprintf("%3.1f %s", v, unit)
+}
+
+// Stop stops the progress bars after repainting them once more.
+func (p *MultiDownloadProgress) Stop() {
+	p.mu.Lock()
+	if p.running && p.quit != nil {
+		close(p.quit)
+		p.quit = nil
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// ProgressGroup shows multiple stacked progress bars that are repainted together, such as an overall progress bar and a progress bar for the current item.
+type ProgressGroup struct {
+	p      Progress // holds the style and options and handles the interrupt signal
+	rows   []*ProgressRow
+	lines  int // number of lines currently drawn
+	frame  int
+	paused bool
+	mu     sync.Mutex
+}
+
+// ProgressRow is a progress bar of a ProgressGroup.
+type ProgressRow struct {
+	g            *ProgressGroup
+	prefix       []byte
+	value, total int64
+	bytes        bool
+}
+
+func NewProgressGroup(style ProgressStyle, opts ...ProgressOption) *ProgressGroup {
+	g := &ProgressGroup{}
+	g.p.style = style.Append
+	g.p.apply(opts)
+	return g
+}
+
+// AddRow adds a progress bar that counts items, where total is the total number of items or zero if unknown.
+func (g *ProgressGroup) AddRow(prefix string, total int64) *ProgressRow {
+	return g.addRow(prefix, total, false)
+}
+
+// AddByteRow adds a progress bar that counts bytes, where total is the total number of bytes or zero if unknown.
+func (g *ProgressGroup) AddByteRow(prefix string, total int64) *ProgressRow {
+	return g.addRow(prefix, total, true)
+}
+
+func (g *ProgressGroup) addRow(prefix string, total int64, bytes bool) *ProgressRow {
+	row := &ProgressRow{
+		g:      g,
+		prefix: []byte(prefix),
+		total:  total,
+		bytes:  bytes,
+	}
+	g.lock()
+	g.rows = append(g.rows, row)
+	g.render(false)
+	g.unlock()
+	return row
+}
+
+// lock locks the output and the progress bars, in that order.
+func (g *ProgressGroup) lock() {
+	outputMu.Lock()
+	g.mu.Lock()
+}
+
+func (g *ProgressGroup) unlock() {
+	g.mu.Unlock()
+	outputMu.Unlock()
+}
+
+// Start starts the progress bars, the interrupt signal is handled once for the whole group.
+func (g *ProgressGroup) Start() {
+	if !g.p.active.CompareAndSwap(false, true) {
+		return
+	}
+
+	setActiveProgress(g)
+	g.p.c = make(chan os.Signal, 1)
+	signal.Notify(g.p.c, os.Interrupt)
+	g.p.wg.Add(1)
+	go func() {
+		defer g.p.wg.Done()
+		if _, interrupt := <-g.p.c; interrupt {
+			g.p.stop()
+			unsetActiveProgress(g)
+			g.lock()
+			g.render(true)
+			g.paused = false
+			g.unlock()
+			raiseInterrupt()
+		}
+	}()
+
+	g.lock()
+	g.lines = 0
+	g.paused = false
+	g.render(false)
+	g.unlock()
+}
+
+// Stop stops the progress bars after repainting them once more.
+func (g *ProgressGroup) Stop() {
+	if g.p.stop() {
+		unsetActiveProgress(g)
+		close(g.p.c)
+		g.p.wg.Wait()
+		g.lock()
+		g.render(true)
+		g.paused = false
+		g.unlock()
+	}
+}
+
+// Pause erases the progress bars so that the caller can write output freely until Resume is called. Progress updates while paused are not drawn.
+func (g *ProgressGroup) Pause() {
+	g.lock()
+	defer g.unlock()
+	if g.p.active.Load() && !g.paused {
+		g.paused = true
+		g.erase()
+	}
+}
+
+// Resume redraws the progress bars after Pause below any output written in the meantime.
+func (g *ProgressGroup) Resume() {
+	g.lock()
+	defer g.unlock()
+	if g.paused {
+		g.paused = false
+		g.render(false)
+	}
+}
+
+// printLine prints a line above the progress bars, which must be called with the output lock held.
+func (g *ProgressGroup) printLine(msg string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.p.isPlain() || !g.p.active.Load() || g.paused {
+		fmt.Fprintln(g.p.writer(), msg)
+		return
+	}
+	g.erase()
+	fmt.Fprintln(g.p.writer(), msg)
+	g.render(false)
+}
+
+// erase erases the drawn progress bars, which must be called with the locks held.
+func (g *ProgressGroup) erase() {
+	if 0 < g.lines && !g.p.isPlain() {
+		fmt.Fprintf(g.p.writer(), escMoveUpN+escMoveStart+escClearBelow, g.lines)
+	}
+	g.lines = 0
+}
+
+// render repaints all rows, which must be called with the locks held. In plain log mode the rows are logged periodically and when final.
+func (g *ProgressGroup) render(final bool) {
+	if !final && (!g.p.active.Load() || g.paused) {
+		return
+	} else if g.p.isPlain() {
+		interval := g.p.logInterval
+		if interval == 0 {
+			interval = progressLogInterval
+		}
+		if final || g.p.logTime.IsZero() || interval <= time.Since(g.p.logTime) {
+			for _, row := range g.rows {
+				fmt.Fprintf(g.p.writer(), "%s%s\n", row.prefix, row.suffix())
+			}
+			g.p.logTime = time.Now()
+		}
+		return
+	}
+	width, _ := g.p.terminalWidth()
+
+	// repaint block of lines, the cursor is below the block
+	buf := g.p.buf[:0]
+	if 0 < g.lines {
+		buf = fmt.Appendf(buf, escMoveUpN, g.lines)
+	}
+	for _, row := range g.rows {
+		buf = append(buf, escMoveStart+escClearLine...)
+		buf = renderBar(buf, row.prefix, row.suffix(), g.p.style, width, row.fraction(), g.frame)
+		buf = append(buf, '\n')
+	}
+	g.p.writer().Write(buf)
+	g.p.buf = buf
+	g.lines = len(g.rows)
+	g.frame++
+}
+
+// fraction returns the progress between 0 and 1, or NaN if the total is unknown.
+func (r *ProgressRow) fraction() float64 {
+	if r.total <= 0 {
+		return math.NaN()
+	}
+	return math.Min(1.0, float64(r.value)/float64(r.total))
+}
+
+func (r *ProgressRow) suffix() []byte {
+	format := func(n int64) string {
+		if r.bytes {
+			return formatSize(n)
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	if r.total <= 0 {
+		return fmt.Appendf(nil, " %v", format(r.value))
+	}
+	return fmt.Appendf(nil, " %v/%v %3.0f%%", format(r.value), format(r.total), r.fraction()*100.0)
+}
+
+func (r *ProgressRow) Add(value int64) {
+	r.g.lock()
+	r.value += value
+	r.g.render(false)
+	r.g.unlock()
+}
+
+func (r *ProgressRow) Set(value int64) {
+	r.g.lock()
+	r.value = value
+	r.g.render(false)
+	r.g.unlock()
+}
+
+// SetTotal sets the total, or zero if unknown.
+func (r *ProgressRow) SetTotal(total int64) {
+	r.g.lock()
+	r.total = total
+	r.g.render(false)
+	r.g.unlock()
+}
+
+// SetPrefix sets the prefix, such as the name of the current item.
+func (r *ProgressRow) SetPrefix(prefix string) {
+	r.g.lock()
+	r.prefix = append(r.prefix[:0], prefix...)
+	r.g.render(false)
+	r.g.unlock()
 }
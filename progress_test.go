@@ -0,0 +1,505 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPercentProgressConcurrentAdd(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPercentProgress("Test", 8000, DefaultProgressStyle, WithWriter(&buf))
+	p.Start()
+
+	var wg sync.WaitGroup
+	for k := 0; k < 8; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				p.Add(1)
+			}
+		}()
+	}
+	for k := 0; k < 10; k++ {
+		// stopping prints the suffix while the value is added to
+		p.Stop()
+		p.Start()
+	}
+	wg.Wait()
+	p.Stop()
+
+	if p.value != 8000 {
+		t.Fatalf("value %v, expected 8000", p.value)
+	}
+	if suffix := string(p.suffix); suffix != " 100%" {
+		t.Fatalf("suffix %q, expected %q", suffix, " 100%")
+	}
+	if !strings.Contains(buf.String(), "Test 100%\n") {
+		t.Fatalf("output %q misses the final progress", buf.String())
+	}
+}
+
+func TestProgressFinishOnce(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgress("Test ", "", DefaultProgressStyle, WithWriter(&buf))
+	p.Start()
+	p.Finish(errors.New("failed"))
+	if !strings.Contains(buf.String(), "Test: failed\n") {
+		t.Fatalf("output %q misses the error", buf.String())
+	}
+
+	buf.Reset()
+	p.Start()
+	p.Stop()
+	if strings.Contains(buf.String(), "failed") {
+		t.Fatalf("output %q repeats the error after a restart", buf.String())
+	}
+}
+
+func TestUploadProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewUploadProgress("Upload ", 3000, bytes.NewReader(make([]byte, 2000)), DefaultProgressStyle, WithWriter(&buf))
+	p.SetOffset(1000)
+	if _, err := io.Copy(io.Discard, p); err != nil {
+		t.Fatal(err)
+	}
+	if p.active.Load() {
+		t.Fatalf("progress bar is active after EOF")
+	} else if p.value != 3000 || p.offset != 1000 {
+		t.Fatalf("value %v and offset %v, expected 3000 and 1000", p.value, p.offset)
+	}
+	if out := buf.String(); !strings.Contains(out, "3.0 kB / 3.0 kB") {
+		t.Fatalf("output %q misses the transferred size", out)
+	}
+}
+
+func TestProgressGroupInterrupt(t *testing.T) {
+	interrupted := make(chan struct{})
+	restore := onInterrupt
+	OnInterrupt(func() {
+		close(interrupted)
+	})
+	defer OnInterrupt(restore)
+
+	var buf bytes.Buffer
+	g := NewProgressGroup(DefaultProgressStyle, WithWriter(&buf))
+	row := g.AddRow("Items ", 100)
+	g.Start()
+	row.Add(100)
+	g.p.c <- os.Interrupt
+	<-interrupted
+	if currentProgress() != nil {
+		t.Fatalf("progress group is active after an interrupt")
+	} else if out := buf.String(); !strings.HasSuffix(out, "Items  100/100 100%\n") {
+		t.Fatalf("output %q misses the final progress", out)
+	}
+}
+
+func TestProgressGroupPause(t *testing.T) {
+	var buf bytes.Buffer
+	g := NewProgressGroup(DefaultProgressStyle, WithWriter(&buf))
+	row := g.AddRow("Items ", 100)
+	g.Start()
+	if currentProgress() != g {
+		t.Fatalf("progress group is not active")
+	}
+
+	resume := pauseProgress()
+	resumeNested := pauseProgress()
+	resumeNested()
+	if !g.paused {
+		t.Fatalf("progress group resumed by a nested prompt")
+	}
+	resume()
+	if g.paused {
+		t.Fatalf("progress group not resumed")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			row.Add(1)
+		}
+	}()
+	for j := 0; j < 10; j++ {
+		SafePrintf("line %d", j)
+	}
+	wg.Wait()
+	g.Stop()
+	if currentProgress() != nil {
+		t.Fatalf("progress group is active after Stop")
+	}
+	if out := buf.String(); !strings.Contains(out, "line 9\n") || !strings.Contains(out, "Items  100/100 100%\n") {
+		t.Fatalf("output %q misses the lines or the final progress", out)
+	}
+}
+
+func TestMultiDownloadProgressActive(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewMultiDownloadProgress(DefaultProgressStyle, WithWriter(&buf))
+	r := p.AddReader("File ", bytes.NewReader(make([]byte, 1000)), 1000)
+	if currentProgress() != p {
+		t.Fatalf("progress bars are not active")
+	}
+	resume := pauseProgress()
+	if !p.paused {
+		t.Fatalf("progress bars not paused")
+	}
+	resume()
+
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	p.Stop()
+	if currentProgress() != nil {
+		t.Fatalf("progress bars are active after Stop")
+	}
+}
+
+func TestMultiDownloadProgressPlain(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewMultiDownloadProgress(DefaultProgressStyle, WithWriter(&buf))
+	r := p.AddReader("File ", bytes.NewReader(make([]byte, 1000)), 1000)
+	if _, err := io.ReadFull(r, make([]byte, 500)); err != nil {
+		t.Fatal(err)
+	}
+
+	p.lock()
+	p.render()
+	out := buf.String()
+	p.unlock()
+	var item, total bool
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "File ") && strings.HasSuffix(line, " 50%") {
+			item = true
+		} else if line == "Total    500.0 B,  50%" {
+			total = true
+		}
+	}
+	if !item || !total {
+		t.Fatalf("output %q misses the intermediate progress lines", out)
+	}
+
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	p.Stop()
+	if out := buf.String(); !strings.Contains(out, "File ✓ 1.0 kB in ") || !strings.Contains(out, "Total  Done: 1.0 kB in ") {
+		t.Fatalf("output %q misses the final lines", out)
+	}
+}
+
+func TestDownloadProgress(t *testing.T) {
+	body := make([]byte, 5000)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write(body)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/file", http.StatusFound)
+	})
+	mux.HandleFunc("/chunked", func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < len(body); i += 1000 {
+			w.Write(body[i : i+1000])
+			w.(http.Flusher).Flush()
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		path string
+		size int64
+	}{
+		{"/file", 5000},
+		{"/redirect", 5000},
+		{"/chunked", -1},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := contentLength(resp); size != tt.size {
+			t.Errorf("%v: content length %v, expected %v", tt.path, size, tt.size)
+		}
+
+		var buf bytes.Buffer
+		p := NewDownloadProgress("Download ", resp, DefaultProgressStyle, WithWriter(&buf))
+		n, err := io.Copy(io.Discard, p)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%v: %v", tt.path, err)
+		} else if n != int64(len(body)) || p.value != n {
+			t.Errorf("%v: read %v bytes and counted %v, expected %v", tt.path, n, p.value, len(body))
+		} else if p.active.Load() {
+			t.Errorf("%v: progress bar is active after EOF", tt.path)
+		}
+	}
+}
+
+func TestDownloadProgressEmptyRead(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewReaderProgress("Download ", &emptyReader{}, 100, DefaultProgressStyle, WithWriter(&buf)).(*readerProgress)
+	if n, err := p.Read(make([]byte, 10)); n != 0 || err != nil {
+		t.Fatalf("read %v bytes with error %v", n, err)
+	} else if !p.active.Load() {
+		t.Fatalf("progress bar stopped by an empty read")
+	}
+	p.Close()
+}
+
+// emptyReader returns no bytes and no error.
+type emptyReader struct{}
+
+func (r *emptyReader) Read(b []byte) (int, error) {
+	return 0, nil
+}
+
+func TestRateWindow(t *testing.T) {
+	t0 := time.Unix(0, 0)
+	w := newRateWindow(t0)
+	tests := []struct {
+		dt    time.Duration
+		value int64
+		rate  float64
+	}{
+		{time.Second, 1000, 1000.0},
+		{2 * time.Second, 1500, 750.0},
+		{3 * time.Second, 6000, 2000.0},
+		{4 * time.Second, 8000, 2000.0},
+		{5 * time.Second, 10000, 2000.0},
+		{6 * time.Second, 10000, 10000.0 / 6.0}, // the window keeps one sample older than its duration
+		{7 * time.Second, 10000, 9000.0 / 6.0},  // first sample leaves the window
+		{20 * time.Second, 10000, 0.0},          // stalled
+		{21 * time.Second, 11000, 1000.0 / 14.0},
+	}
+	for _, tt := range tests {
+		if rate := w.add(t0.Add(tt.dt), tt.value); math.Abs(rate-tt.rate) > 1e-9 {
+			t.Errorf("at %v: rate %v, expected %v", tt.dt, rate, tt.rate)
+		}
+	}
+}
+
+func TestTransferProgressAverage(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewReaderProgress("Download ", bytes.NewReader(make([]byte, 2000)), 0, DefaultProgressStyle, WithWriter(&buf)).(*readerProgress)
+	p.t = time.Now().Add(-2 * time.Second) // started two seconds ago
+	if _, err := io.Copy(io.Discard, p); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "1.0 kB/s") {
+		t.Fatalf("output %q misses the average rate", out)
+	}
+}
+
+func TestTimerProgressRemaining(t *testing.T) {
+	p := NewTimerProgress("Wait ", 3*time.Second, DefaultProgressStyle)
+	tests := []struct {
+		elapsed time.Duration
+		suffix  string
+	}{
+		{0, " 3s left"},
+		{500 * time.Millisecond, " 3s left"},
+		{time.Second, " 2s left"},
+		{2900 * time.Millisecond, " 1s left"},
+		{5 * time.Second, " 0s left"},
+	}
+	for _, tt := range tests {
+		p.update(tt.elapsed)
+		if suffix := string(p.suffix); suffix != tt.suffix {
+			t.Errorf("after %v: suffix %q, expected %q", tt.elapsed, suffix, tt.suffix)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n      int64
+		binary bool
+		value  float64
+		unit   string
+	}{
+		{0, false, 0, "B"},
+		{512, false, 512, "B"},
+		{999, false, 999, "B"},
+		{1000, false, 1, "kB"},
+		{1023, false, 1.023, "kB"},
+		{1024, false, 1.024, "kB"},
+		{1_000_000, false, 1, "MB"},
+		{1_000_000_000_000, false, 1, "TB"},
+		{1_000_000_000_000_000, false, 1, "PB"},
+		{math.MaxInt64, false, math.MaxInt64 / 1e15, "PB"},
+		{-999, false, -999, "B"},
+		{-1000, false, -1, "kB"},
+		{999, true, 999, "B"},
+		{1000, true, 1000, "B"},
+		{1023, true, 1023, "B"},
+		{1024, true, 1, "KiB"},
+		{1 << 20, true, 1, "MiB"},
+		{1 << 40, true, 1, "TiB"},
+		{math.MaxInt64, true, math.MaxInt64 / float64(1<<50), "PiB"},
+		{-1024, true, -1, "KiB"},
+	}
+	defer SetBinaryUnits(false)
+	for _, tt := range tests {
+		SetBinaryUnits(tt.binary)
+		if value, unit := formatBytes(tt.n); math.Abs(value-tt.value) > 1e-9 || unit != tt.unit {
+			t.Errorf("%v (binary %v): %v %v, expected %v %v", tt.n, tt.binary, value, unit, tt.value, tt.unit)
+		}
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	tests := []struct {
+		n     int64
+		value float64
+		unit  string
+	}{
+		{0, 0, "b"},
+		{999, 999, "b"},
+		{1000, 1, "kb"},
+		{1023, 1.023, "kb"},
+		{1024, 1.024, "kb"},
+		{1_000_000_000, 1, "Gb"},
+		{math.MaxInt64, math.MaxInt64 / 1e15, "Pb"},
+		{-999, -999, "b"},
+		{-1000, -1, "kb"},
+	}
+	for _, tt := range tests {
+		if value, unit := formatBits(tt.n); math.Abs(value-tt.value) > 1e-9 || unit != tt.unit {
+			t.Errorf("%v: %v %v, expected %v %v", tt.n, value, unit, tt.value, tt.unit)
+		}
+	}
+}
+
+func TestRenderBarResize(t *testing.T) {
+	prefix, suffix := []byte("Download "), []byte(" 50%")
+	style := ProgressStyle(DefaultProgressStyle).Append
+	for _, w := range []int{80, 40, 16, 13, 12, 9, 4, 0, 80} {
+		line := renderBar(nil, prefix, suffix, style, w, 0.5, 0)
+		if n := stringWidth(string(line)); n != w {
+			t.Errorf("width %v: line %q has width %v", w, line, n)
+		}
+		if len(prefix)+len(suffix) < w && !bytes.HasSuffix(line, suffix) {
+			t.Errorf("width %v: line %q misses the suffix", w, line)
+		}
+	}
+
+	if line := clampLine([]byte("✓ 3 completed"), 5); string(line) != "✓ 3 c" {
+		t.Errorf("clamped line %q, expected %q", line, "✓ 3 c")
+	}
+}
+
+func TestProgressStyleAnimation(t *testing.T) {
+	custom := ProgressStyle(func(b []byte, f float64) {
+		for i := range b {
+			b[i] = '.'
+		}
+		b[0], b[len(b)-1] = '(', ')'
+	})
+	tests := []struct {
+		style ProgressStyle
+		frame int
+		bar   string
+	}{
+		{nil, 0, "[###    ]"},
+		{DefaultProgressStyle, 2, "[  ###  ]"},
+		{DefaultProgressStyle, 6, "[  ###  ]"},
+		{custom, 0, "(###....)"},
+		{custom, 4, "(....###)"},
+		{custom, 5, "(...###.)"},
+	}
+	for _, tt := range tests {
+		if bar := string(tt.style.Append(nil, 9, math.NaN(), tt.frame)); bar != tt.bar {
+			t.Errorf("frame %v: bar %q, expected %q", tt.frame, bar, tt.bar)
+		}
+	}
+	if bar := string(custom.Append(nil, 9, 0.5, 3)); bar != "(.......)" {
+		t.Errorf("bar %q, expected the style without animation", bar)
+	}
+}
+
+func TestPercentProgressSuffix(t *testing.T) {
+	tests := []struct {
+		value, maximum int
+		suffix         string
+	}{
+		{0, 100, "   0%"},
+		{7, 100, "   7%"},
+		{50, 100, "  50%"},
+		{100, 100, " 100%"},
+		{150, 100, " 100%"},
+		{-5, 100, "   0%"},
+		{5, 0, "   ?%"},
+	}
+	for _, tt := range tests {
+		p := NewPercentProgress("Test", tt.maximum, DefaultProgressStyle)
+		p.Set(tt.value)
+		if suffix := string(p.suffix); suffix != tt.suffix {
+			t.Errorf("%v of %v: suffix %q, expected %q", tt.value, tt.maximum, suffix, tt.suffix)
+		}
+	}
+}
+
+func TestProgressLifecycle(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPercentProgress("Test", 10, DefaultProgressStyle, WithWriter(&buf))
+	p.Stop() // before Start
+	for k := 0; k < 3; k++ {
+		p.Reset()
+		p.Start()
+		if !p.active.Load() {
+			t.Fatalf("run %d: progress bar is not active after Start", k)
+		}
+		p.Add(10)
+		p.Stop()
+		p.Stop()
+		if p.active.Load() {
+			t.Fatalf("run %d: progress bar is active after Stop", k)
+		}
+	}
+	if n := strings.Count(buf.String(), "Test 100%\n"); n != 3 {
+		t.Fatalf("output %q has %d final lines, expected 3", buf.String(), n)
+	}
+
+	buf.Reset()
+	p.Start()
+	p.Finish(nil)
+	p.Finish(nil)
+	if out := buf.String(); out != statusMarker(true, false)+" Test\n" {
+		t.Fatalf("output %q, expected a single success line", out)
+	}
+}
+
+func TestTimerProgressRestart(t *testing.T) {
+	p := NewTimerProgress("Wait ", 20*time.Millisecond, DefaultProgressStyle, WithWriter(io.Discard))
+	for k := 0; k < 2; k++ {
+		p.Start()
+		p.Wait()
+		if p.active.Load() {
+			t.Fatalf("run %d: timer is active after Wait", k)
+		}
+	}
+	p.Start()
+	p.Stop()
+	p.Stop()
+	if p.active.Load() {
+		t.Fatalf("timer is active after Stop")
+	}
+}
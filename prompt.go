@@ -2,7 +2,10 @@ package prompt
 
 import (
 	"bufio"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"reflect"
@@ -16,21 +19,79 @@ import (
 
 var selectMaxLines = 25    // maximum number of lines to show
 var selectScrollOffset = 5 // minimum number of lines above/below cursor
-var optionSelected = fmt.Sprintf("%v[\u00D7] %%v%v", escBold, escReset)
+var optionSelected = Bold.sprintf("[\u00D7] %%v")
 var optionUnselected = "[ ] %v"
-var keyInterrupt = fmt.Errorf("interrupt")
-var keyEscape = fmt.Errorf("escape")
+
+var accessible = false
+
+// dateHint is shown when the input of a time.Time prompt is empty.
+var dateHint = "e.g. 2006-01-02, Jan 2 2006, 01/02/2006 15:04, ..."
+
+var dateLocation = time.Local
+
+// SetDateParseLocale sets the time zone in which dates without a time zone are parsed by prompts for time.Time, which is the local time zone by default.
+func SetDateParseLocale(loc *time.Location) {
+	if loc == nil {
+		loc = time.Local
+	}
+	dateLocation = loc
+}
+
+// SetAccessible switches all prompts and progress bars to an accessible text mode for screen readers. Select and Checklist print numbered options and read the option numbers, and progress bars print percentages at every 25%.
+func SetAccessible(enable bool) {
+	accessible = enable
+}
+
+// ErrInterrupt is returned when the user interrupts the prompt with Ctrl+C.
+var ErrInterrupt = fmt.Errorf("interrupt")
+
+// ErrEscape is returned when the user quits the prompt with Escape.
+var ErrEscape = fmt.Errorf("escape")
+
+var onInterrupt = func() {
+	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+}
+
+// OnInterrupt sets the function that is called after a prompt is interrupted by Ctrl+C or a progress bar is stopped by an interrupt signal. By default the interrupt signal is raised again for the process, which terminates it unless the application handles the signal. Set it to nil to only return ErrInterrupt from prompts and stop drawing progress bars, so that the application's own signal handling proceeds.
+func OnInterrupt(f func()) {
+	onInterrupt = f
+}
+
+func raiseInterrupt() {
+	if onInterrupt != nil {
+		onInterrupt()
+	}
+}
 
 // Enter is a prompt that requires the Enter key to continue.
 func Enter(label string) {
+	defer pauseProgress()()
+
 	fmt.Printf("%v [enter]: ", label)
 
+	if scriptReader != nil {
+		readScriptLine()
+		fmt.Printf("\n")
+		return
+	}
+
 	var res string
 	fmt.Scanln(&res)
 }
 
-// YesNo is a prompt that requires a yes or no answer. It returns true for any of (1,y,yes,t,true), and false for any of (0,n,no,f,false). It is case-insensitive.
+// YesNo is a prompt that requires a yes or no answer. It returns true for any of (1,y,yes,t,true), and false for any of (0,n,no,f,false). It is case-insensitive. When reading from a script, the default is returned at the end of the script or for an invalid answer, and the error is kept for ScriptErr.
 func YesNo(label string, deflt bool) bool {
+	b, err := YesNoErr(label, deflt)
+	if err != nil && scriptReader != nil && scriptErr == nil {
+		scriptErr = err
+	}
+	return b
+}
+
+// YesNoErr is like YesNo but returns an error instead of the default when reading from a script, at the end of the script or for an invalid answer.
+func YesNoErr(label string, deflt bool) (bool, error) {
+	defer pauseProgress()()
+
 	first := true
 
 Prompt:
@@ -42,7 +103,16 @@ Prompt:
 	fmt.Printf(escSavePos)
 
 	var res string
-	fmt.Scanln(&res)
+	if scriptReader != nil {
+		var err error
+		if res, err = readScriptLine(); err != nil {
+			fmt.Printf("\n")
+			return deflt, err
+		}
+		fmt.Printf("%v\n", res)
+	} else {
+		fmt.Scanln(&res)
+	}
 	res = strings.TrimSpace(res)
 
 	if res == "" {
@@ -52,27 +122,160 @@ Prompt:
 		} else {
 			fmt.Printf("%v [y/N]: no\n", label)
 		}
-		return deflt
-	} else {
-		res = strings.ToLower(res)
+		return deflt, nil
 	}
 
 	var b bool
 	var err error
-	if res == "y" || res == "yes" {
+	switch strings.ToLower(res) {
+	case "1", "y", "yes", "t", "true":
 		b = true
-	} else if res == "n" || res == "no" {
+	case "0", "n", "no", "f", "false":
 		b = false
+	default:
+		err = fmt.Errorf("invalid answer '%v'", res)
 	}
-	if err != nil {
+	if err != nil && scriptReader != nil {
+		fmt.Printf("%v\n", (Red|Bold).sprintf("ERROR: %v", err))
+		return deflt, err
+	} else if err != nil {
 		first = false
-		fmt.Printf("%v%v%vERROR: %v%v%v", escClearLine, escRed, escBold, err, escReset, escMoveUp)
+		fmt.Printf("%v%v%v", escClearLine, (Red|Bold).sprintf("ERROR: %v", err), escMoveUp)
 		fmt.Printf(escMoveStart + escClearLine)
 		goto Prompt
 	} else if !first {
 		fmt.Printf(escClearLine) // clear error
 	}
-	return b
+	return b, nil
+}
+
+// Answer is an answer to a YesNoAll prompt.
+type Answer int
+
+// Answers to a YesNoAll prompt.
+const (
+	No Answer = iota
+	Yes
+	All
+	Quit
+)
+
+// String returns the answer as a lowercase word.
+func (a Answer) String() string {
+	switch a {
+	case Yes:
+		return "yes"
+	case All:
+		return "all"
+	case Quit:
+		return "quit"
+	}
+	return "no"
+}
+
+// parseAnswer returns the answer for a word or its first letter. It is case-insensitive.
+func parseAnswer(s string, answers []Answer) (Answer, bool) {
+	s = strings.ToLower(s)
+	for _, a := range answers {
+		if s == a.String() || s == a.String()[:1] {
+			return a, true
+		}
+	}
+	return No, false
+}
+
+// YesNoKey is like YesNo but answers with a single key press of y or n without requiring Enter, and Enter selects the default answer. The answer is written after the label. It returns ErrEscape for Escape and ErrInterrupt for Ctrl+C. If standard input is not a terminal, a line is read instead.
+func YesNoKey(label string, deflt bool) (bool, error) {
+	answer := No
+	if deflt {
+		answer = Yes
+	}
+	answer, err := keyAnswer(label, []Answer{Yes, No}, answer)
+	return answer == Yes, err
+}
+
+// YesNoAll is a prompt for batch operations that requires an answer of yes, no, all for yes to all remaining items, or quit. A single key press of (y,n,a,q) answers without requiring Enter, and Enter selects the default answer. The answer is written after the label. It returns ErrEscape for Escape and ErrInterrupt for Ctrl+C. If standard input is not a terminal, a line is read instead.
+func YesNoAll(label string, deflt Answer) (Answer, error) {
+	return keyAnswer(label, []Answer{Yes, No, All, Quit}, deflt)
+}
+
+// keyAnswer prompts for one of the answers with a single key press, or reads a line in script mode or if standard input is not a terminal.
+func keyAnswer(label string, answers []Answer, deflt Answer) (Answer, error) {
+	defer pauseProgress()()
+
+	keys := []string{}
+	for _, a := range answers {
+		if a == deflt {
+			keys = append(keys, strings.ToUpper(a.String()[:1]))
+		} else {
+			keys = append(keys, a.String()[:1])
+		}
+	}
+	fmt.Printf("%v [%v]: ", label, strings.Join(keys, "/"))
+
+	var err error
+	restore := func() error { return nil }
+	if scriptReader == nil {
+		restore, err = MakeRawTerminal(false)
+	}
+	if scriptReader != nil || err != nil {
+		var line string
+		if scriptReader != nil {
+			line, err = readScriptLine()
+		} else if _, err = fmt.Scanln(&line); err != io.EOF {
+			err = nil
+		}
+		if err != nil {
+			fmt.Printf("\n")
+			return deflt, err
+		} else if line = strings.TrimSpace(line); line == "" {
+			fmt.Printf("%v\n", deflt)
+			return deflt, nil
+		} else if answer, ok := parseAnswer(line, answers); ok {
+			fmt.Printf("%v\n", answer)
+			return answer, nil
+		}
+		fmt.Printf("%v\n%v\n", line, (Red | Bold).sprintf("ERROR: invalid answer"))
+		return deflt, fmt.Errorf("invalid answer")
+	}
+
+	answer := deflt
+	func() {
+		defer restore()
+
+		input := bufio.NewReader(os.Stdin)
+		for {
+			var r rune
+			if r, _, err = input.ReadRune(); err != nil {
+				return
+			} else if r == '\x03' { // interrupt
+				err = ErrInterrupt
+				return
+			} else if r == '\r' || r == '\n' { // default
+				return
+			} else if r == '\x1B' { // escape
+				if input.Buffered() == 0 {
+					err = ErrEscape
+					return
+				}
+				input.Discard(input.Buffered()) // ignore escape sequences such as arrow keys
+			} else if a, ok := parseAnswer(string(r), answers); ok {
+				answer = a
+				return
+			}
+		}
+	}()
+
+	if err != nil {
+		if err == ErrInterrupt {
+			fmt.Printf("^C")
+			raiseInterrupt()
+		}
+		fmt.Printf("\n")
+		return deflt, err
+	}
+	fmt.Printf("%v\n", answer)
+	return answer, nil
 }
 
 type defaultValue struct {
@@ -100,12 +303,54 @@ func DefaultWithCaret(idst, ideflt interface{}, pos int) defaultValue {
 	return defaultValue{idst, ideflt, pos}
 }
 
-// Prompt is a regular text prompt that can read into a (string,[]byte,bool,int,int8,int16,int32,int64,uint,uint8,uint16,uint32,uint64,float32,float64,time.Time) or a type that implements the Scanner interface. The idst must be a pointer to a variable, its value determines the default/initial value.
+// PromptOption is an option for Prompt that is given together with the destination by WithOptions, such as Sensitive, WithDateFormat, or AsyncValidator.
+type PromptOption func(*promptOptions)
+
+type promptOptions struct {
+	layout    string // date layout
+	sensitive bool
+	async     []Validator // run after the other validators
+}
+
+type optionsValue struct {
+	idst interface{}
+	opts []PromptOption
+}
+
+// WithOptions sets the options for Prompt, where idst is the destination or the default value given by Default.
+func WithOptions(idst interface{}, opts ...PromptOption) optionsValue {
+	if v, ok := idst.(optionsValue); ok {
+		return optionsValue{v.idst, append(v.opts[:len(v.opts):len(v.opts)], opts...)}
+	}
+	return optionsValue{idst, opts}
+}
+
+// unwrapOptions returns the destination or default value given to WithOptions and its options.
+func unwrapOptions(idst interface{}) (interface{}, []PromptOption) {
+	if v, ok := idst.(optionsValue); ok {
+		return v.idst, v.opts
+	}
+	return idst, nil
+}
+
+func applyOptions(opts []PromptOption) promptOptions {
+	o := promptOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Prompt is a regular text prompt that can read into a (string,[]byte as hexadecimal,bool,int,int8,int16,int32,int64,uint,uint8,uint16,uint32,uint64,float32,float64,complex64,complex128,time.Time) or a type that implements the Scanner interface. The idst must be a pointer to a variable, its value determines the default/initial value.
 // The initial value will be editable in-place. To set the text caret initial position when idst is editable, use prompt.Default(value, position). When editing, you can use the Left or Ctrl+B, Right or Ctrl+F, Home or Ctrl+A, End or Ctrl+E to move around; Backspace and Delete to delete a character; Ctrl+U and Ctrl+K to delete from the caret to the beginning and the end of the line respectively; Ctrl+C and Escape to quit; and Ctrl+Z and Enter to confirm the input.
-// All validators must be satisfies, otherwise an error is printed and the answer should be corrected.
+// All validators must be satisfies, otherwise an error is printed and the answer should be corrected. Options such as Sensitive are given by wrapping the destination with WithOptions.
 func Prompt(idst interface{}, label string, validators ...Validator) error {
+	defer pauseProgress()()
+
 	first := true
 
+	idst, popts := unwrapOptions(idst)
+	opts := applyOptions(popts)
 	pos := -1
 	hasDeflt := false
 	var ideflt interface{}
@@ -130,7 +375,7 @@ func Prompt(idst interface{}, label string, validators ...Validator) error {
 	switch idst.(type) {
 	case nil:
 		// ignore
-	case []byte, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Time:
+	case []byte, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, complex64, complex128, time.Time:
 		editDefault = true
 	default:
 		if _, ok := idst.(interface {
@@ -143,19 +388,49 @@ func Prompt(idst interface{}, label string, validators ...Validator) error {
 		}
 	}
 
+	layout := ""
+	if _, ok := idst.(time.Time); ok {
+		layout = opts.layout
+	}
+
+	// display returns the input as shown, which is masked for sensitive prompts
+	sensitive := opts.sensitive
+	display := func(rs []rune) string {
+		if sensitive {
+			return strings.Repeat("*", len(rs))
+		}
+		return string(rs)
+	}
+
 	var result []rune
 	if editDefault {
 		switch deflt := ideflt.(type) {
 		case nil:
 			// no-op
+		case time.Time:
+			if deflt.IsZero() {
+				// no-op, an unset date shows the hint
+			} else if layout != "" {
+				result = []rune(deflt.Format(layout))
+			} else {
+				result = []rune(fmt.Sprint(deflt))
+			}
 		case []byte:
-			result = []rune(string(deflt))
+			result = []rune(hex.EncodeToString(deflt))
 		case string:
 			result = []rune(deflt)
 		default:
 			result = []rune(fmt.Sprint(ideflt))
 		}
 	}
+	_, hexInput := idst.([]byte)
+	hint := ""
+	if layout != "" {
+		hint = "e.g. " + layout
+	} else if _, ok := idst.(time.Time); ok {
+		hint = dateHint
+	}
+	var suggestion []rune // accepted with Tab after a Suggest error
 	if pos == -1 {
 		pos = len(result)
 	} else if pos < 0 {
@@ -179,116 +454,152 @@ Prompt:
 		result = []rune{}
 		pos = 0
 	} else {
-		fmt.Printf("%v: %v", label, string(result))
+		fmt.Printf("%v: %v", label, display(result))
 		fmt.Printf(strings.Repeat(escMoveLeft, len(result)-pos))
 	}
 
-	// make raw and hide input
-	restore, err := MakeRawTerminal(false)
-	if err != nil {
-		return err
-	}
-
-	func() {
-		defer restore()
+	var err error
+	if scriptReader != nil {
+		// read answer from script
+		var line string
+		if line, err = readScriptLine(); err == nil && line != "" {
+			fmt.Printf(strings.Repeat(escMoveLeft, pos)+escClearToEnd+"%v", display([]rune(line)))
+			result = []rune(line)
+			pos = len(result)
+		}
+	} else {
+		// make raw and hide input
+		var restore func() error
+		restore, err = MakeRawTerminal(false)
+		if err != nil {
+			return err
+		}
 
-		// read input
-		input := bufio.NewReader(os.Stdin)
-		for {
-			var r rune
-			if r, _, err = input.ReadRune(); err != nil {
-				break
+		cols := 0 // terminal width for the byte count of hex input
+		if hexInput {
+			if _, n, terr := TerminalSize(); terr == nil {
+				cols = n
 			}
+		}
 
-			if r == '\x03' { // interrupt
-				err = keyInterrupt
-				break
-			} else if r == '\x04' || r == '\r' || r == '\n' { // select
-				break
-			} else if r == '\x7F' { // backspace
-				if pos != 0 {
-					result = append(result[:pos-1], result[pos:]...)
-					pos--
-					fmt.Printf(escMoveLeft+"%v "+strings.Repeat(escMoveLeft, len(result)+1-pos), string(result[pos:]))
+		func() {
+			defer restore()
+
+			// read input
+			input := bufio.NewReader(os.Stdin)
+			for {
+				if 0 < cols {
+					drawByteCount(len(result)/2, stringWidth(label)+2+len(result), cols)
 				}
-			} else if r == '\x1B' { // escape
-				if input.Buffered() == 0 {
-					err = keyEscape
+				if hint != "" && len(result) == 0 {
+					fmt.Printf(escSavePos+"%v"+escRestorePos, Dim.sprintf("%v", hint))
+				}
+
+				var r rune
+				if r, _, err = input.ReadRune(); err != nil {
 					break
-				} else if r, _, err = input.ReadRune(); err != nil {
+				} else if hint != "" && len(result) == 0 {
+					fmt.Printf(escClearToEnd)
+				}
+
+				if r == '\x03' { // interrupt
+					err = ErrInterrupt
 					break
-				} else if r == '[' { // CSI
+				} else if r == '\x04' || r == '\r' || r == '\n' { // select
+					break
+				} else if r == '\x7F' { // backspace
+					if pos != 0 {
+						result = append(result[:pos-1], result[pos:]...)
+						pos--
+						fmt.Printf(escMoveLeft+"%v "+strings.Repeat(escMoveLeft, len(result)+1-pos), display(result[pos:]))
+					}
+				} else if r == '\x1B' { // escape
 					if input.Buffered() == 0 {
-						// ignore
+						err = ErrEscape
+						break
 					} else if r, _, err = input.ReadRune(); err != nil {
 						break
-					} else if r == 'D' { // left
-						if pos != 0 {
-							fmt.Printf(escMoveLeft)
-							pos--
-						}
-					} else if r == 'C' { // right
-						if pos != len(result) {
-							fmt.Printf(escMoveRight)
-							pos++
-						}
-					} else if r == 'H' { // home
-						fmt.Printf(strings.Repeat(escMoveLeft, pos))
-						pos = 0
-					} else if r == 'F' { // end
-						fmt.Printf(strings.Repeat(escMoveRight, len(result)-pos))
-						pos = len(result)
-					} else if r == '3' {
+					} else if r == '[' { // CSI
 						if input.Buffered() == 0 {
 							// ignore
 						} else if r, _, err = input.ReadRune(); err != nil {
 							break
-						} else if r == '~' { // delete
+						} else if r == 'D' { // left
+							if pos != 0 {
+								fmt.Printf(escMoveLeft)
+								pos--
+							}
+						} else if r == 'C' { // right
 							if pos != len(result) {
+								fmt.Printf(escMoveRight)
+								pos++
+							}
+						} else if r == 'H' { // home
+							fmt.Printf(strings.Repeat(escMoveLeft, pos))
+							pos = 0
+						} else if r == 'F' { // end
+							fmt.Printf(strings.Repeat(escMoveRight, len(result)-pos))
+							pos = len(result)
+						} else if r == '3' {
+							if input.Buffered() == 0 {
+								// ignore
+							} else if r, _, err = input.ReadRune(); err != nil {
+								break
+							} else if r == '~' { // delete
+								if pos != len(result) {
 
-								result = append(result[:pos], result[pos+1:]...)
-								fmt.Printf("%v "+strings.Repeat(escMoveLeft, len(result)+1-pos), string(result[pos:]))
+									result = append(result[:pos], result[pos+1:]...)
+									fmt.Printf("%v "+strings.Repeat(escMoveLeft, len(result)+1-pos), display(result[pos:]))
+								}
 							}
 						}
 					}
+				} else if r == '\x01' { // Ctrl+A - move to start of line
+					fmt.Printf(strings.Repeat(escMoveLeft, pos))
+					pos = 0
+				} else if r == '\x02' { // Ctrl+B - move back
+					fmt.Printf(escMoveLeft)
+					pos--
+				} else if r == '\x05' { // Ctrl+E - move to end of line
+					fmt.Printf(strings.Repeat(escMoveRight, len(result)-pos))
+					pos = len(result)
+				} else if r == '\x06' { // Ctrl+F - move forward
+					fmt.Printf(escMoveRight)
+					pos++
+				} else if r == '\x0B' { // Ctrl+K - delete to end of line
+					fmt.Printf(strings.Repeat(" ", len(result)-pos))
+					fmt.Printf(strings.Repeat(escMoveLeft, len(result)-pos))
+					result = result[:pos]
+				} else if r == '\x15' { // Ctrl+U - delete to start of line
+					fmt.Printf(strings.Repeat(escMoveLeft, pos))
+					fmt.Printf("%v"+strings.Repeat(" ", pos), display(result[pos:]))
+					fmt.Printf(strings.Repeat(escMoveLeft, len(result)))
+					result = result[pos:]
+					pos = 0
+				} else if r == '\t' && suggestion != nil { // Tab - accept suggestion
+					fmt.Printf(strings.Repeat(escMoveLeft, pos)+escClearToEnd+"%v", display(suggestion))
+					result = suggestion
+					pos = len(result)
+					break
+				} else if ' ' <= r {
+					result = append(result[:pos], append([]rune{r}, result[pos:]...)...)
+					fmt.Printf("%v"+strings.Repeat(escMoveLeft, len(result)-pos-1), display(result[pos:]))
+					pos++
 				}
-			} else if r == '\x01' { // Ctrl+A - move to start of line
-				fmt.Printf(strings.Repeat(escMoveLeft, pos))
-				pos = 0
-			} else if r == '\x02' { // Ctrl+B - move back
-				fmt.Printf(escMoveLeft)
-				pos--
-			} else if r == '\x05' { // Ctrl+E - move to end of line
-				fmt.Printf(strings.Repeat(escMoveRight, len(result)-pos))
-				pos = len(result)
-			} else if r == '\x06' { // Ctrl+F - move forward
-				fmt.Printf(escMoveRight)
-				pos++
-			} else if r == '\x0B' { // Ctrl+K - delete to end of line
-				fmt.Printf(strings.Repeat(" ", len(result)-pos))
-				fmt.Printf(strings.Repeat(escMoveLeft, len(result)-pos))
-				result = result[:pos]
-			} else if r == '\x15' { // Ctrl+U - delete to start of line
-				fmt.Printf(strings.Repeat(escMoveLeft, pos))
-				fmt.Printf("%v"+strings.Repeat(" ", pos), string(result[pos:]))
-				fmt.Printf(strings.Repeat(escMoveLeft, len(result)))
-				result = result[pos:]
-				pos = 0
-			} else if ' ' <= r {
-				result = append(result[:pos], append([]rune{r}, result[pos:]...)...)
-				fmt.Printf("%v"+strings.Repeat(escMoveLeft, len(result)-pos-1), string(result[pos:]))
-				pos++
 			}
-		}
-	}()
+			if 0 < cols {
+				drawByteCount(-1, stringWidth(label)+2+len(result), cols)
+			}
+		}()
+	}
 
 	if err != nil {
 		if !first {
 			fmt.Printf(escMoveDown + escClearLine + escMoveUp)
 		}
-		if err == keyInterrupt {
+		if err == ErrInterrupt {
 			fmt.Printf(strings.Repeat(escMoveRight, len(result)-pos) + "^C")
-			syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+			raiseInterrupt()
 		}
 		fmt.Printf("\n")
 		return err
@@ -300,135 +611,15 @@ Prompt:
 	res := strings.TrimSpace(string(result))
 	ival := ideflt
 	if editDefault || res != "" || ival == nil {
-		switch idst.(type) {
-		case []byte:
-			ival = []byte(res)
-		case string:
-			ival = res
-		case bool:
-			var b bool
-			if res == "y" || res == "Y" || res == "yes" || res == "YES" {
-				b = true
-			} else if res == "n" || res == "N" || res == "no" || res == "NO" {
-				b = false
-			} else {
-				var perr error
-				b, perr = strconv.ParseBool(res)
-				if perr != nil {
-					err = fmt.Errorf("invalid boolean")
-				}
-			}
-			ival = b
-		case int:
-			i, perr := strconv.ParseInt(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid integer")
-			} else if math.MaxInt < i {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = int(i)
-		case int8:
-			i, perr := strconv.ParseInt(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid integer")
-			} else if math.MaxInt8 < i {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = int8(i)
-		case int16:
-			i, perr := strconv.ParseInt(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid integer")
-			} else if math.MaxInt16 < i {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = int16(i)
-		case int32:
-			i, perr := strconv.ParseInt(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid integer")
-			} else if math.MaxInt64 < i {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = int32(i)
-		case int64:
-			i, perr := strconv.ParseInt(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid integer")
-			}
-			ival = i
-		case uint:
-			u, perr := strconv.ParseUint(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid positive integer")
-			} else if math.MaxInt < u {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = uint(u)
-		case uint8:
-			u, perr := strconv.ParseUint(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid positive integer")
-			} else if math.MaxInt8 < u {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = uint8(u)
-		case uint16:
-			u, perr := strconv.ParseUint(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid positive integer")
-			} else if math.MaxInt16 < u {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = uint16(u)
-		case uint32:
-			u, perr := strconv.ParseUint(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid positive integer")
-			} else if math.MaxInt64 < u {
-				err = fmt.Errorf("integer overflow")
-			}
-			ival = uint32(u)
-		case uint64:
-			u, perr := strconv.ParseUint(res, 10, 64)
-			if perr != nil {
-				err = fmt.Errorf("invalid positive integer")
-			}
-			ival = u
-		case float32:
-			f, perr := strconv.ParseFloat(res, 32)
-			if perr.(*strconv.NumError).Err == strconv.ErrRange {
-				err = fmt.Errorf("floating point overflow")
-			} else if perr != nil {
-				err = fmt.Errorf("invalid floating point")
-			}
-			ival = float32(f)
-		case float64:
-			f, perr := strconv.ParseFloat(res, 64)
-			if perr.(*strconv.NumError).Err == strconv.ErrRange {
-				err = fmt.Errorf("floating point overflow")
-			} else if perr != nil {
-				err = fmt.Errorf("invalid floating point")
-			}
-			ival = f
-		case time.Time:
-			t, perr := dateparse.ParseAny(res)
-			if perr != nil {
-				err = fmt.Errorf("invalid datetime")
-			}
-			ival = t
-		default:
-			if scanner, ok := dst.Interface().(interface {
-				Scan(interface{}) error
-			}); ok {
-				// already sets value to dst
-				if perr := scanner.Scan(res); perr != nil {
-					err = fmt.Errorf("invalid %T: %w", idst, perr)
-				}
-				ival = dst.Elem().Interface()
-			} else {
-				return fmt.Errorf("unsupported destination type: %T", idst)
+		if layout != "" {
+			if ival, err = time.ParseInLocation(layout, res, dateLocation); err != nil {
+				err = fmt.Errorf("invalid datetime, expected format %v", layout)
 			}
+		} else {
+			ival, err = parseValue(idst, dst, res)
+		}
+		if errors.Is(err, errUnsupportedType) {
+			return err
 		}
 	} else if deflt, ok := ideflt.(bool); ok {
 		fmt.Printf(escMoveUp + escMoveStart + escClearLine)
@@ -447,11 +638,41 @@ Prompt:
 				break
 			}
 		}
+		if err == nil && 0 < len(opts.async) {
+			spinner := scriptReader == nil && !accessible
+			if spinner {
+				// show spinner after the answer
+				fmt.Printf(escMoveUp+escMoveToCol, stringWidth(label)+4+stringWidth(display(result)))
+			}
+			for _, validator := range opts.async {
+				if verr := validator(ival); verr != nil {
+					err = verr
+					break
+				}
+			}
+			if spinner {
+				fmt.Printf(escMoveDown + escMoveStart)
+			}
+			if err == ErrInterrupt {
+				fmt.Printf(escClearLine)
+				return err
+			}
+		}
 	}
 
-	if err != nil {
+	if err != nil && scriptReader != nil {
+		fmt.Printf("%v%v\n", escClearLine, (Red|Bold).sprintf("ERROR: %v", err))
+		return err
+	} else if err != nil {
 		first = false
-		fmt.Printf("%v%v%vERROR: %v%v%v", escClearLine, escRed, escBold, err, escReset, escMoveUp)
+		msg := err.Error()
+		suggestion = nil
+		var serr *Suggest
+		if errors.As(err, &serr) && 0 < len(serr.Candidates) {
+			msg += fmt.Sprintf(", did you mean '%v'? [tab]", strings.Join(serr.Candidates, "' or '"))
+			suggestion = []rune(serr.Candidates[0])
+		}
+		fmt.Printf("%v%v%v", escClearLine, (Red|Bold).sprintf("ERROR: %v", msg), escMoveUp)
 		fmt.Printf(escMoveStart + escClearLine)
 		goto Prompt
 	} else if !first {
@@ -460,3 +681,198 @@ Prompt:
 	dst.Elem().Set(reflect.ValueOf(ival))
 	return nil
 }
+
+// parseComplex parses a complex number in the format real+imagi, such as 1+2i or (1+2i), or in the format (real,imag), such as (1,2). The bit size is 64 for complex64 and 128 for complex128.
+func parseComplex(s string, bitSize int) (complex128, error) {
+	if 2 < len(s) && s[0] == '(' && s[len(s)-1] == ')' {
+		if re, im, ok := strings.Cut(s[1:len(s)-1], ","); ok {
+			r, err := strconv.ParseFloat(strings.TrimSpace(re), bitSize/2)
+			if err != nil {
+				return 0, err
+			}
+			i, err := strconv.ParseFloat(strings.TrimSpace(im), bitSize/2)
+			if err != nil {
+				return 0, err
+			}
+			return complex(r, i), nil
+		}
+	}
+	return strconv.ParseComplex(s, bitSize)
+}
+
+// drawByteCount shows the number of bytes at the right margin of the line, after the input of the given width if it fits. A negative number only clears the margin.
+func drawByteCount(n, width, cols int) {
+	if cols <= width {
+		return
+	}
+	s := ""
+	if n == 1 {
+		s = "1 byte"
+	} else if 0 <= n {
+		s = fmt.Sprintf("%d bytes", n)
+	}
+	fmt.Printf(escSavePos+escMoveToCol+escClearToEnd, width+1)
+	if s != "" && width+1 < cols-len(s) {
+		fmt.Printf(escMoveToCol+"%v", cols-len(s)+1, Dim.sprintf("%v", s))
+	}
+	fmt.Printf(escRestorePos)
+}
+
+var errUnsupportedType = fmt.Errorf("unsupported destination type")
+
+// parseValue parses the input into a value of the type of idst, which is the value of the pointer dst. Types implementing the Scanner interface set the value of dst directly.
+func parseValue(idst interface{}, dst reflect.Value, res string) (interface{}, error) {
+	var err error
+	var ival interface{}
+	switch idst.(type) {
+	case []byte:
+		b, perr := hex.DecodeString(res)
+		if perr != nil {
+			err = fmt.Errorf("invalid hexadecimal")
+		}
+		ival = b
+	case string:
+		ival = res
+	case bool:
+		var b bool
+		if res == "y" || res == "Y" || res == "yes" || res == "YES" {
+			b = true
+		} else if res == "n" || res == "N" || res == "no" || res == "NO" {
+			b = false
+		} else {
+			var perr error
+			b, perr = strconv.ParseBool(res)
+			if perr != nil {
+				err = fmt.Errorf("invalid boolean")
+			}
+		}
+		ival = b
+	case int:
+		i, perr := strconv.ParseInt(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid integer")
+		} else if math.MaxInt < i {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = int(i)
+	case int8:
+		i, perr := strconv.ParseInt(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid integer")
+		} else if math.MaxInt8 < i {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = int8(i)
+	case int16:
+		i, perr := strconv.ParseInt(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid integer")
+		} else if math.MaxInt16 < i {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = int16(i)
+	case int32:
+		i, perr := strconv.ParseInt(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid integer")
+		} else if math.MaxInt64 < i {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = int32(i)
+	case int64:
+		i, perr := strconv.ParseInt(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid integer")
+		}
+		ival = i
+	case uint:
+		u, perr := strconv.ParseUint(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid positive integer")
+		} else if math.MaxInt < u {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = uint(u)
+	case uint8:
+		u, perr := strconv.ParseUint(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid positive integer")
+		} else if math.MaxInt8 < u {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = uint8(u)
+	case uint16:
+		u, perr := strconv.ParseUint(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid positive integer")
+		} else if math.MaxInt16 < u {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = uint16(u)
+	case uint32:
+		u, perr := strconv.ParseUint(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid positive integer")
+		} else if math.MaxInt64 < u {
+			err = fmt.Errorf("integer overflow")
+		}
+		ival = uint32(u)
+	case uint64:
+		u, perr := strconv.ParseUint(res, 10, 64)
+		if perr != nil {
+			err = fmt.Errorf("invalid positive integer")
+		}
+		ival = u
+	case float32:
+		f, perr := strconv.ParseFloat(res, 32)
+		if perr != nil && perr.(*strconv.NumError).Err == strconv.ErrRange {
+			err = fmt.Errorf("floating point overflow")
+		} else if perr != nil {
+			err = fmt.Errorf("invalid floating point")
+		}
+		ival = float32(f)
+	case float64:
+		f, perr := strconv.ParseFloat(res, 64)
+		if perr != nil && perr.(*strconv.NumError).Err == strconv.ErrRange {
+			err = fmt.Errorf("floating point overflow")
+		} else if perr != nil {
+			err = fmt.Errorf("invalid floating point")
+		}
+		ival = f
+	case complex64:
+		c, perr := parseComplex(res, 64)
+		if perr != nil && perr.(*strconv.NumError).Err == strconv.ErrRange {
+			err = fmt.Errorf("complex number overflow")
+		} else if perr != nil {
+			err = fmt.Errorf("invalid complex number")
+		}
+		ival = complex64(c)
+	case complex128:
+		c, perr := parseComplex(res, 128)
+		if perr != nil && perr.(*strconv.NumError).Err == strconv.ErrRange {
+			err = fmt.Errorf("complex number overflow")
+		} else if perr != nil {
+			err = fmt.Errorf("invalid complex number")
+		}
+		ival = c
+	case time.Time:
+		t, perr := dateparse.ParseIn(res, dateLocation)
+		if perr != nil {
+			err = fmt.Errorf("invalid datetime")
+		}
+		ival = t
+	default:
+		if scanner, ok := dst.Interface().(interface {
+			Scan(interface{}) error
+		}); ok {
+			// already sets value to dst
+			if perr := scanner.Scan(res); perr != nil {
+				err = fmt.Errorf("invalid %T: %w", idst, perr)
+			}
+			ival = dst.Elem().Interface()
+		} else {
+			return nil, fmt.Errorf("%w: %T", errUnsupportedType, idst)
+		}
+	}
+	return ival, err
+}
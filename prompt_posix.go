@@ -10,39 +10,54 @@ import (
 )
 
 var (
-	escClearLine  = "\x1B[2K"
-	escClearToEnd = "\x1B[0K"
-	escMoveUp     = "\x1B[1A"
-	escMoveUpN    = "\x1B[%dA"
-	escMoveDown   = "\x1B[1B"
-	escMoveDownN  = "\x1B[%dB"
-	escMoveLeft   = "\x1B[1D"
-	escMoveRight  = "\x1B[1C"
-	escMoveStart  = "\x1B[G"
-	escMoveToCol  = "\x1B[%dG"
-	escSavePos    = "\x1B[s"
-	escRestorePos = "\x1B[u"
-	escBold       = "\x1B[1m"
-	escRed        = "\x1B[31m"
-	escReset      = "\x1B[0m"
-	escShow       = "\x1B[?25h"
-	escHide       = "\x1B[?25l"
+	escClearLine   = "\x1B[2K"
+	escClearToEnd  = "\x1B[0K"
+	escClearBelow  = "\x1B[0J"
+	escMoveUp      = "\x1B[1A"
+	escMoveUpN     = "\x1B[%dA"
+	escMoveDown    = "\x1B[1B"
+	escMoveDownN   = "\x1B[%dB"
+	escMoveLeft    = "\x1B[1D"
+	escMoveRight   = "\x1B[1C"
+	escMoveStart   = "\x1B[G"
+	escMoveToCol   = "\x1B[%dG"
+	escSavePos     = "\x1B[s"
+	escRestorePos  = "\x1B[u"
+	escBold        = "\x1B[1m"
+	escDim         = "\x1B[2m"
+	escRed         = "\x1B[31m"
+	escGreen       = "\x1B[32m"
+	escUnderline   = "\x1B[4m"
+	escNoUnderline = "\x1B[24m"
+	escReset       = "\x1B[0m"
+	escShow        = "\x1B[?25h"
+	escHide        = "\x1B[?25l"
 )
 
 func TerminalSize() (int, int, error) {
+	return terminalSize(uintptr(syscall.Stdin))
+}
+
+// terminalSize returns the number of rows and columns of the terminal with the given file descriptor.
+func terminalSize(fd uintptr) (int, int, error) {
 	data := struct {
 		Row    uint16
 		Col    uint16
 		Xpixel uint16
 		Ypixel uint16
 	}{}
-	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL, uintptr(syscall.Stdin), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&data))); err != 0 {
+	if _, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&data))); err != 0 {
 		return 0, 0, err
 	}
 	return int(data.Row), int(data.Col), nil
 }
 
 func MakeRawTerminal(hide bool) (func() error, error) {
+	return makeRawTerminal(hide, 1, 0)
+}
+
+// makeRawTerminal is like MakeRawTerminal but sets the minimum number of bytes and the timeout in deciseconds of a read.
+func makeRawTerminal(hide bool, vmin, vtime uint8) (func() error, error) {
 	if hide {
 		fmt.Printf(escHide)
 	}
@@ -57,10 +72,11 @@ func MakeRawTerminal(hide bool) (func() error, error) {
 	newState := syscall.Termios{}
 	newState.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG
 	// Because we are clearing canonical mode, we need to ensure VMIN & VTIME are
-	// set to the values we expect. This combination puts things in standard
-	// "blocking read" mode (see termios(3)).
-	newState.Cc[syscall.VMIN] = 1
-	newState.Cc[syscall.VTIME] = 0
+	// set to the values we expect. VMIN=1 and VTIME=0 puts things in standard
+	// "blocking read" mode, while VMIN=0 and VTIME>0 returns after a timeout
+	// (see termios(3)).
+	newState.Cc[syscall.VMIN] = vmin
+	newState.Cc[syscall.VTIME] = vtime
 
 	if _, _, err := syscall.Syscall6(syscall.SYS_IOCTL, uintptr(syscall.Stdin), syscall.TCSETS, uintptr(unsafe.Pointer(&newState)), 0, 0, 0); err != 0 {
 		if hide {
@@ -0,0 +1,104 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestYesNoScript(t *testing.T) {
+	SetScriptReader(strings.NewReader("yes\nN\n\nmaybe\n"))
+	defer SetScriptReader(nil)
+
+	for j, expected := range []bool{true, false, true} {
+		if b, err := YesNoErr("Continue", true); err != nil {
+			t.Fatalf("answer %d: %v", j, err)
+		} else if b != expected {
+			t.Fatalf("answer %d: %v, expected %v", j, b, expected)
+		}
+	}
+	if _, err := YesNoErr("Continue", true); err == nil || err.Error() != "invalid answer 'maybe'" {
+		t.Fatalf("error %v for a mismatched answer", err)
+	} else if _, err := YesNoErr("Continue", true); err == nil || err.Error() != "script: no more answers" {
+		t.Fatalf("error %v at the end of the script", err)
+	}
+}
+
+func TestYesNoScriptErr(t *testing.T) {
+	SetScriptReader(strings.NewReader("maybe\n"))
+	defer SetScriptReader(nil)
+
+	if b := YesNo("Continue", true); !b {
+		t.Fatalf("invalid answer does not return the default")
+	} else if err := ScriptErr(); err == nil || err.Error() != "invalid answer 'maybe'" {
+		t.Fatalf("error %v for a mismatched answer", err)
+	}
+	if b := YesNo("Continue", false); b {
+		t.Fatalf("end of the script does not return the default")
+	} else if err := ScriptErr(); err == nil || err.Error() != "invalid answer 'maybe'" {
+		t.Fatalf("error %v, expected the first error to be kept", err)
+	}
+
+	SetScriptReader(strings.NewReader("y\n"))
+	if YesNo("Continue", false) != true || ScriptErr() != nil {
+		t.Fatalf("error %v after setting a new script", ScriptErr())
+	}
+}
+
+func TestPromptSensitive(t *testing.T) {
+	SetScriptReader(strings.NewReader("\n"))
+	defer SetScriptReader(nil)
+
+	token := ""
+	if err := Prompt(WithOptions(Default(&token, "secret"), Sensitive()), "Token"); err != nil {
+		t.Fatal(err)
+	} else if token != "secret" {
+		t.Fatalf("token %q, expected the default", token)
+	}
+}
+
+func TestPromptDateFormat(t *testing.T) {
+	SetScriptReader(strings.NewReader("2026-10-16\n16/10/2026\n"))
+	defer SetScriptReader(nil)
+
+	date := time.Time{}
+	if err := Prompt(WithOptions(&date, WithDateFormat("2006-01-02")), "Date"); err != nil {
+		t.Fatal(err)
+	} else if date.Year() != 2026 || date.Month() != time.October || date.Day() != 16 {
+		t.Fatalf("date %v, expected 2026-10-16", date)
+	}
+	if err := Prompt(WithOptions(&date, WithDateFormat("2006-01-02")), "Date"); err == nil || err.Error() != "invalid datetime, expected format 2006-01-02" {
+		t.Fatalf("error %v for a date in another format", err)
+	}
+}
+
+func TestPromptAsyncValidator(t *testing.T) {
+	SetScriptReader(strings.NewReader("a\nbb\ncc\n"))
+	defer SetScriptReader(nil)
+
+	calls := 0
+	dst := WithOptions(new(string), AsyncValidator(func(ctx context.Context, i any) error {
+		calls++
+		if i.(string) == "cc" {
+			return errors.New("taken")
+		}
+		return nil
+	}, time.Second))
+	tests := []struct {
+		err   string
+		calls int
+	}{
+		{"too short, minimum is 2", 0},
+		{"", 1},
+		{"taken", 2},
+	}
+	for j, tt := range tests {
+		if err := Prompt(dst, "Name", StrLength(2, 10)); err == nil && tt.err != "" || err != nil && err.Error() != tt.err {
+			t.Fatalf("answer %d: error %v, expected %q", j, err, tt.err)
+		} else if calls != tt.calls {
+			t.Fatalf("answer %d: %d async calls, expected %d", j, calls, tt.calls)
+		}
+	}
+}
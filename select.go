@@ -2,14 +2,21 @@ package prompt
 
 import (
 	"fmt"
+	"os"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 func getSelected(dst, options reflect.Value) (int, error) {
 	var selected int
 	if dst.Type() == options.Type().Elem() {
 		for i := 0; i < options.Len(); i++ {
-			if options.Index(i).Equal(dst) {
+			if equalValue(options.Index(i), dst) {
 				selected = i
 				break
 			}
@@ -29,9 +36,87 @@ func getSelected(dst, options reflect.Value) (int, error) {
 	return selected, nil
 }
 
+// equalValue returns true if the values are equal, comparing values that are not comparable, such as the rows of tabular options, by their contents.
+func equalValue(a, b reflect.Value) bool {
+	if a.Comparable() && b.Comparable() {
+		return a.Equal(b)
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// SelectOption is an option for Select.
+type SelectOption func(*selectOptions)
+
+type selectOptions struct {
+	headers  []string
+	minWidth int
+}
+
+// WithHeaders displays a header row above the options. For tabular options, where each option is a slice of columns, there is one header per column. Otherwise, a single header is displayed.
+func WithHeaders(headers []string) SelectOption {
+	return func(o *selectOptions) {
+		o.headers = headers
+	}
+}
+
+// WithMinOptionWidth pads options with spaces to have at least the given width.
+func WithMinOptionWidth(n int) SelectOption {
+	return func(o *selectOptions) {
+		o.minWidth = n
+	}
+}
+
+// formatOptions returns the options as strings. If the options are slices they are formatted as aligned columns, and the header is aligned to the columns.
+func formatOptions(options reflect.Value, headers []string) ([]string, string) {
+	strs := make([]string, options.Len())
+	if k := options.Type().Elem().Kind(); k != reflect.Slice && k != reflect.Array || options.Type().Elem().Elem().Kind() == reflect.Uint8 {
+		for i := 0; i < options.Len(); i++ {
+			strs[i] = fmt.Sprint(options.Index(i).Interface())
+		}
+		return strs, strings.Join(headers, " ")
+	}
+
+	// tabular options
+	cells := make([][]string, options.Len())
+	widths := make([]int, len(headers))
+	for j, header := range headers {
+		widths[j] = stringWidth(header)
+	}
+	for i := 0; i < options.Len(); i++ {
+		row := options.Index(i)
+		cells[i] = make([]string, row.Len())
+		for j := 0; j < row.Len(); j++ {
+			cells[i][j] = fmt.Sprint(row.Index(j).Interface())
+			if len(widths) <= j {
+				widths = append(widths, 0)
+			}
+			widths[j] = Max(widths[j], stringWidth(cells[i][j]))
+		}
+	}
+	alignColumns := func(cols []string) string {
+		sb := strings.Builder{}
+		for j, col := range cols {
+			if j != 0 {
+				sb.WriteString("  ")
+			}
+			sb.WriteString(col)
+			if j+1 < len(cols) {
+				sb.WriteString(strings.Repeat(" ", widths[j]-stringWidth(col)))
+			}
+		}
+		return sb.String()
+	}
+	for i := range cells {
+		strs[i] = alignColumns(cells[i])
+	}
+	return strs, alignColumns(headers)
+}
+
 // Select is a list selection prompt that allows to select one of the list of possible values. The ioptions must be a slice of options. The idst must be a pointer to a variable and must of the same type as the options (set the option value) or an integer (set the option index). The value od idst determines the initial selected value.
 // Users can select an option using Up or W or K to move up, Down or S or J to move down, Tab and Shift+Tab to move down and up respectively and wrap around, Ctrl+C or Escape to quit, and Ctrl+Z or Enter to select an option.
-func Select(idst interface{}, label string, ioptions interface{}) error {
+func Select(idst interface{}, label string, ioptions interface{}, opts ...SelectOption) error {
+	defer pauseProgress()()
+
 	dst := reflect.ValueOf(idst)
 	options := reflect.ValueOf(ioptions)
 	if dst.Kind() != reflect.Pointer {
@@ -43,9 +128,19 @@ func Select(idst interface{}, label string, ioptions interface{}) error {
 	}
 	dst = dst.Elem()
 
-	optionStrings := make([]string, options.Len())
-	for i := 0; i < options.Len(); i++ {
-		optionStrings[i] = fmt.Sprint(options.Index(i).Interface())
+	o := selectOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	optionStrings, header := formatOptions(options, o.headers)
+	for i, option := range optionStrings {
+		if n := stringWidth(option); n < o.minWidth {
+			optionStrings[i] += strings.Repeat(" ", o.minWidth-n)
+		}
+	}
+	hdr := 0
+	if header != "" {
+		hdr = 1
 	}
 
 	selected, err := getSelected(dst, options)
@@ -53,38 +148,52 @@ func Select(idst interface{}, label string, ioptions interface{}) error {
 		return err
 	}
 
-	// set constants
-	maxLines := selectMaxLines
-	if _, rows, err := TerminalSize(); err != nil {
-		return err
-	} else if rows-1 < maxLines {
-		maxLines = rows - 1 // keep one for prompt row
-	}
-	scrollOffset := selectScrollOffset
-	withQuery := maxLines < options.Len() || 10 < options.Len()
-	exitEnter := true
-
-	err = terminalList(label, optionStrings, selected, maxLines, scrollOffset, withQuery, exitEnter, func(i, selected int) string {
-		if i == selected {
-			return optionSelected
+	if scriptReader != nil {
+		fmt.Printf("%v: ", label)
+		var line string
+		if line, err = readScriptLine(); err == nil && strings.TrimSpace(line) != "" {
+			selected, err = scriptChoice(line, optionStrings)
 		}
-		return optionUnselected
-	}, func(r rune, i int) {
-		if r == '\n' || r == '\r' {
-			selected = i
+		fmt.Printf(escMoveStart + escClearLine)
+	} else if accessible {
+		var choices []int
+		if choices, err = accessibleList(label, optionStrings, false); err == nil && choices != nil {
+			selected = choices[0]
 		}
-	})
+	} else {
+		// set constants
+		maxLines := selectMaxLines
+		if _, rows, err := TerminalSize(); err != nil {
+			return err
+		} else if rows-1-hdr < maxLines {
+			maxLines = rows - 1 - hdr // keep one for prompt row and one for the header
+		}
+		scrollOffset := selectScrollOffset
+		withQuery := maxLines < options.Len() || 10 < options.Len()
+		exitEnter := true
+
+		err = terminalList(label, header, "", optionStrings, selected, maxLines, scrollOffset, withQuery, exitEnter, func(i, selected int) string {
+			if i == selected {
+				return optionSelected
+			}
+			return optionUnselected
+		}, func(r rune, i int) {
+			if r == '\n' || r == '\r' {
+				selected = i
+			}
+		}, nil)
+	}
 
 	fmt.Printf("%v: ", label)
 	if err != nil {
-		if err == keyInterrupt {
+		if err == ErrInterrupt {
 			fmt.Printf("^C")
 		}
 		fmt.Printf("\n")
 		return err
 	}
 
-	fmt.Printf("%v\n", optionStrings[selected])
+	fmt.Printf("%v\n", strings.TrimRight(optionStrings[selected], " "))
 
 	if dst.Type() == options.Type().Elem() {
 		dst.Set(options.Index(selected))
@@ -100,3 +209,227 @@ func Select(idst interface{}, label string, ioptions interface{}) error {
 	}
 	return nil
 }
+
+var selectLazyDebounce = 150 * time.Millisecond // delay after the last keypress before options are requested
+
+// SelectLazy is a list selection prompt like Select, but the options are requested from source for the query typed by the user. The source is called each time the query changes after a short delay, and its results replace the current options, which allows searching remote options without loading them all upfront. The idst must be a pointer to a string variable.
+// Users can type to change the query, use Up and Down or Tab and Shift+Tab to move through the options, Ctrl+C to quit, Escape to keep the current value, and Enter to select an option.
+func SelectLazy(idst interface{}, label string, source func(query string) ([]string, error)) error {
+	defer pauseProgress()()
+
+	dst := reflect.ValueOf(idst)
+	if dst.Kind() != reflect.Pointer || dst.Elem().Kind() != reflect.String {
+		return fmt.Errorf("destination must be a pointer to a string")
+	}
+	dst = dst.Elem()
+
+	answer := dst.String()
+	var err error
+	if scriptReader != nil {
+		fmt.Printf("%v: ", label)
+		var line string
+		if line, err = readScriptLine(); err == nil && strings.TrimSpace(line) != "" {
+			var options []string
+			if options, err = source(strings.TrimSpace(line)); err == nil {
+				var selected int
+				if selected, err = scriptChoice(line, options); err == nil {
+					answer = options[selected]
+				}
+			}
+		}
+		fmt.Printf(escMoveStart + escClearLine)
+	} else if accessible {
+		var query string
+		if err = Prompt(&query, label+" (search)"); err == nil {
+			var options []string
+			if options, err = source(query); err == nil && len(options) == 0 {
+				err = fmt.Errorf("no options")
+			} else if err == nil {
+				var choices []int
+				if choices, err = accessibleList(label, options, false); err == nil && choices != nil {
+					answer = options[choices[0]]
+				}
+			}
+		}
+	} else {
+		var ok bool
+		if ok, answer, err = lazyList(label, source); err == nil && !ok {
+			answer = dst.String()
+		}
+	}
+
+	fmt.Printf("%v: ", label)
+	if err != nil {
+		if err == ErrInterrupt {
+			fmt.Printf("^C")
+		}
+		fmt.Printf("\n")
+		return err
+	}
+	fmt.Printf("%v\n", answer)
+	dst.SetString(answer)
+	return nil
+}
+
+// lazyResult is the result of a request for options.
+type lazyResult struct {
+	query   string
+	options []string
+	err     error
+}
+
+// lazyList draws the query and the options obtained from source, and returns the selected option or false if the user escaped.
+func lazyList(label string, source func(string) ([]string, error)) (bool, string, error) {
+	maxLines := selectMaxLines
+	if _, rows, err := TerminalSize(); err != nil {
+		return false, "", err
+	} else if rows-1 < maxLines {
+		maxLines = rows - 1 // keep one for prompt row
+	}
+
+	// make raw input, reads return every 100ms to allow stopping
+	restore, err := makeRawTerminal(false, 0, 1)
+	if err != nil {
+		return false, "", err
+	}
+	defer restore()
+
+	var wg sync.WaitGroup
+	var stop atomic.Bool
+	keys := make(chan []byte)
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for !stop.Load() {
+			b := make([]byte, 16)
+			if n, _ := os.Stdin.Read(b); 0 < n {
+				select {
+				case keys <- b[:n]:
+				case <-done:
+				}
+			}
+		}
+	}()
+	defer wg.Wait()
+	defer stop.Store(true)
+	defer close(done)
+
+	var query []rune
+	var options []string
+	var sourceErr error
+	selected, drawn := 0, 0
+	pending, searched := true, false
+	results := make(chan lazyResult, 1)
+	request := func(query string) {
+		go func() {
+			options, err := source(query)
+			select {
+			case results <- lazyResult{query, options, err}:
+			case <-done:
+			}
+		}()
+	}
+	request("")
+	debounce := time.NewTimer(selectLazyDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	render := func() {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, escMoveStart+escClearLine+"%v: %v", label, string(query))
+		lines := []string{}
+		if sourceErr != nil {
+			lines = append(lines, Red.sprintf("%v", sourceErr))
+		} else if len(options) == 0 && pending {
+			lines = append(lines, "Searching...")
+		} else if len(options) == 0 && searched {
+			lines = append(lines, Red.sprintf("No options found"))
+		}
+		numLines := Min(maxLines, len(options))
+		windowStart := Clip(selected-(numLines-1)/2, 0, len(options)-numLines)
+		for i := windowStart; i < windowStart+numLines; i++ {
+			markup := optionUnselected
+			if i == selected {
+				markup = optionSelected
+			}
+			lines = append(lines, fmt.Sprintf(markup, highlightMatch(query, options[i])))
+		}
+		for _, line := range lines {
+			sb.WriteString("\n" + escMoveStart + escClearLine + "  " + line)
+		}
+		for i := len(lines); i < drawn; i++ {
+			sb.WriteString("\n" + escClearLine)
+		}
+		if n := Max(len(lines), drawn); 0 < n {
+			fmt.Fprintf(&sb, escMoveUpN, n)
+		}
+		fmt.Fprintf(&sb, escMoveToCol, queryColumn(label, query))
+		fmt.Print(sb.String())
+		drawn = len(lines)
+	}
+	defer func() {
+		// clear output
+		fmt.Printf(escMoveStart + escClearLine + strings.Repeat(escMoveDown+escClearLine, drawn))
+		if 0 < drawn {
+			fmt.Printf(escMoveUpN, drawn)
+		}
+	}()
+
+	for {
+		render()
+		select {
+		case res := <-results:
+			if res.query == string(query) {
+				options, sourceErr = res.options, res.err
+				selected = 0
+				pending, searched = false, true
+			}
+		case <-debounce.C:
+			pending = true
+			request(string(query))
+		case b := <-keys:
+			prevQuery := string(query)
+			for 0 < len(b) {
+				if b[0] == '\x1B' {
+					if len(b) == 1 {
+						return false, "", nil
+					} else if 3 <= len(b) && b[1] == '[' {
+						if b[2] == 'A' || b[2] == 'Z' { // up or shift+tab
+							selected = Max(0, selected-1)
+						} else if b[2] == 'B' { // down
+							selected = Max(0, Min(len(options)-1, selected+1))
+						}
+						b = b[3:]
+					} else {
+						b = b[1:]
+					}
+					continue
+				}
+
+				r, n := utf8.DecodeRune(b)
+				b = b[n:]
+				if r == '\x03' { // interrupt
+					return false, "", ErrInterrupt
+				} else if r == '\r' || r == '\n' || r == '\x04' { // enter
+					if selected < len(options) {
+						return true, options[selected], nil
+					}
+				} else if r == '\t' {
+					selected = Max(0, Min(len(options)-1, selected+1))
+				} else if r == '\x7F' { // backspace
+					if 0 < len(query) {
+						query = query[:len(query)-1]
+					}
+				} else if r == '\x15' { // Ctrl+U
+					query = query[:0]
+				} else if unicode.IsPrint(r) {
+					query = append(query, r)
+				}
+			}
+			if string(query) != prevQuery {
+				debounce.Reset(selectLazyDebounce)
+			}
+		}
+	}
+}
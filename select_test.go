@@ -0,0 +1,29 @@
+package prompt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatOptionsWidth(t *testing.T) {
+	options := [][]string{
+		{"東京", "Japan"},
+		{"Zürich", "Switzerland"},
+		{"NYC", "USA"},
+	}
+	strs, header := formatOptions(reflect.ValueOf(options), []string{"City", "Country"})
+
+	expected := []string{
+		"東京    Japan",
+		"Zürich  Switzerland",
+		"NYC     USA",
+	}
+	for i, str := range strs {
+		if str != expected[i] {
+			t.Errorf("option %d: %q, expected %q", i, str, expected[i])
+		}
+	}
+	if header != "City    Country" {
+		t.Errorf("header %q, expected %q", header, "City    Country")
+	}
+}
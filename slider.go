@@ -0,0 +1,140 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+var sliderWidth = 32 // width of the slider bar including brackets
+
+// NumberSlider is a prompt that selects a number in the range [min,max] (inclusive) using a horizontal slider bar. The idst must be a pointer to an integer or floating point variable, its value determines the initial value.
+// Users can use Left and Right to decrease and increase the value by step respectively, Home and End to go to min and max respectively, type a number directly, Ctrl+C or Escape to quit, and Ctrl+D or Enter to confirm the value.
+func NumberSlider(idst interface{}, label string, min, max, step float64) error {
+	defer pauseProgress()()
+
+	dst := reflect.ValueOf(idst)
+	if dst.Kind() != reflect.Pointer {
+		return fmt.Errorf("destination must be a pointer to a variable")
+	} else if max <= min {
+		return fmt.Errorf("maximum must be larger than minimum")
+	} else if step <= 0.0 {
+		return fmt.Errorf("step must be positive")
+	}
+	dst = dst.Elem()
+
+	var value float64
+	switch kind := dst.Kind(); kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(dst.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = float64(dst.Uint())
+	case reflect.Float32, reflect.Float64:
+		value = dst.Float()
+	default:
+		return fmt.Errorf("unsupported destination type: %v", kind)
+	}
+	value = math.Max(min, math.Min(max, value))
+
+	var typed []rune // number typed by the user
+	var bar []byte
+	render := func() {
+		bar = ProgressStyle(DefaultProgressStyle).Append(bar[:0], sliderWidth, (value-min)/(max-min), 0)
+		text := strconv.FormatFloat(value, 'f', -1, 64)
+		if typed != nil {
+			text = string(typed)
+		}
+		fmt.Printf(escMoveStart+escClearLine+"%v: %s %v", label, bar, text)
+	}
+
+	// make raw and hide input
+	restore, err := MakeRawTerminal(true)
+	if err != nil {
+		return err
+	}
+
+	func() {
+		defer restore()
+
+		// read input
+		input := bufio.NewReader(os.Stdin)
+		for {
+			render()
+
+			var r rune
+			if r, _, err = input.ReadRune(); err != nil {
+				break
+			}
+
+			if r == '\x03' { // interrupt
+				err = ErrInterrupt
+				break
+			} else if r == '\x04' || r == '\r' || r == '\n' { // select
+				break
+			} else if r == '\x7F' { // backspace
+				if 0 < len(typed) {
+					typed = typed[:len(typed)-1]
+				}
+			} else if r == '\x1B' { // escape
+				if input.Buffered() == 0 {
+					err = ErrEscape
+					break
+				} else if r, _, err = input.ReadRune(); err != nil {
+					break
+				} else if r == '[' { // CSI
+					if input.Buffered() == 0 {
+						// ignore
+					} else if r, _, err = input.ReadRune(); err != nil {
+						break
+					} else if r == 'D' { // left
+						value = math.Max(min, value-step)
+						typed = nil
+					} else if r == 'C' { // right
+						value = math.Min(max, value+step)
+						typed = nil
+					} else if r == 'H' { // home
+						value = min
+						typed = nil
+					} else if r == 'F' { // end
+						value = max
+						typed = nil
+					}
+				}
+				continue
+			} else if '0' <= r && r <= '9' || r == '.' || r == '-' {
+				typed = append(typed, r)
+			} else {
+				continue
+			}
+
+			// parse typed number
+			if f, perr := strconv.ParseFloat(string(typed), 64); perr == nil {
+				value = math.Max(min, math.Min(max, f))
+			}
+		}
+	}()
+
+	if err != nil {
+		if err == ErrInterrupt {
+			fmt.Printf("^C")
+		}
+		fmt.Printf("\n")
+		return err
+	}
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = math.Round(value)
+		dst.SetInt(int64(value))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = math.Round(value)
+		dst.SetUint(uint64(value))
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(value)
+	}
+	fmt.Printf(escMoveStart+escClearLine+"%v: %v\n", label, value)
+	return nil
+}
@@ -0,0 +1,341 @@
+package prompt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structField is a prompt for an exported field of a struct, or the start of a section for a nested struct.
+type structField struct {
+	idst       interface{}
+	label      string
+	validators []Validator
+	options    []string
+	section    bool
+}
+
+// structFields returns the prompts for the exported fields of the struct pointed to by idst, in order of declaration. The label is taken from the prompt tag or the field name, the validators from the validate tag, the options from the comma-separated options tag, and the default value from the default tag which is set if the field is the zero value. Fields with the prompt tag "-" are skipped, and nested structs are returned as a section followed by their fields.
+func structFields(idst interface{}) ([]structField, error) {
+	dst := reflect.ValueOf(idst)
+	if dst.Kind() != reflect.Pointer || dst.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("destination must be a pointer to a struct")
+	}
+	dst = dst.Elem()
+
+	fields := []structField{}
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tags, err := parsePromptTag(field.Tag.Get("prompt"))
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", field.Name, err)
+		}
+		lookup := func(key string) (string, bool) {
+			if value, ok := tags[key]; ok {
+				return value, true
+			}
+			return field.Tag.Lookup(key)
+		}
+		label, ok := field.Tag.Lookup("prompt")
+		if tags != nil {
+			label, ok = tags["label"]
+		}
+		if label == "-" {
+			continue
+		} else if !ok || label == "" {
+			label = field.Name
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			subfields, err := structFields(dst.Field(i).Addr().Interface())
+			if err != nil {
+				return nil, fmt.Errorf("field %v: %w", field.Name, err)
+			}
+			fields = append(fields, structField{
+				label:   label,
+				section: true,
+			})
+			fields = append(fields, subfields...)
+			continue
+		}
+
+		validate, _ := lookup("validate")
+		validators, err := parseValidateTag(validate, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %w", field.Name, err)
+		}
+		var options []string
+		if tag, ok := lookup("options"); ok {
+			if 0 < len(validators) {
+				return nil, fmt.Errorf("field %v: validation rules are not supported with options", field.Name)
+			}
+			for _, option := range strings.Split(tag, ",") {
+				options = append(options, strings.TrimSpace(option))
+			}
+		}
+		if deflt, ok := lookup("default"); ok && dst.Field(i).IsZero() {
+			ival, err := parseValue(dst.Field(i).Interface(), dst.Field(i).Addr(), deflt)
+			if err != nil {
+				return nil, fmt.Errorf("field %v: invalid default: %w", field.Name, err)
+			}
+			dst.Field(i).Set(reflect.ValueOf(ival))
+		}
+		fields = append(fields, structField{
+			idst:       dst.Field(i).Addr().Interface(),
+			label:      label,
+			validators: validators,
+			options:    options,
+		})
+	}
+	return fields, nil
+}
+
+// promptTagKeys are the keys of a prompt tag that combines the other tags.
+var promptTagKeys = map[string]bool{
+	"label":    true,
+	"validate": true,
+	"default":  true,
+	"options":  true,
+}
+
+// parsePromptTag parses a prompt tag of the form `label=...,validate=...,default=...,options=...`, where each value runs up to the next key so that it may contain commas. It returns nil if the tag is a plain label.
+func parsePromptTag(tag string) (map[string]string, error) {
+	var tags map[string]string
+	key := ""
+	for _, part := range strings.Split(tag, ",") {
+		if name, value, ok := strings.Cut(part, "="); ok && promptTagKeys[strings.TrimSpace(name)] {
+			key = strings.TrimSpace(name)
+			if tags == nil {
+				tags = map[string]string{}
+			} else if _, ok := tags[key]; ok {
+				return nil, fmt.Errorf("duplicate key '%v' in prompt tag", key)
+			}
+			tags[key] = value
+		} else if key == "" {
+			return nil, nil
+		} else {
+			tags[key] += "," + part
+		}
+	}
+	return tags, nil
+}
+
+// namedValidators are the validation rules without arguments.
+var namedValidators = map[string]func() Validator{
+	"email":     EmailAddress,
+	"url":       func() Validator { return AbsoluteURL() },
+	"ip":        IPAddress,
+	"ipv4":      IPv4Address,
+	"ipv6":      IPv6Address,
+	"hostname":  HostnameOrIP,
+	"domain":    DomainName,
+	"port":      Port,
+	"path":      Path,
+	"abspath":   AbsolutePath,
+	"lowercase": Lowercase,
+	"slug":      Slug,
+}
+
+// parseValidateTag parses a comma-separated list of validation rules for a field of the given type. Supported rules are required, min=N, max=N, minlen=N, maxlen=N, pattern=REGEXP, the ranges numrange(MIN,MAX), intrange(MIN,MAX), uintrange(MIN,MAX), and strlength(MIN,MAX), and the names in namedValidators. The pattern rule must be the last rule since the pattern may contain commas.
+func parseValidateTag(tag string, typ reflect.Type) ([]Validator, error) {
+	validators := []Validator{}
+	min, max := math.NaN(), math.NaN()
+	minlen, maxlen := 0, -1
+	for tag != "" {
+		rule := tag
+		if strings.HasPrefix(tag, "pattern=") {
+			tag = ""
+		} else if i := ruleEnd(tag); i != -1 {
+			rule, tag = tag[:i], tag[i+1:]
+		} else {
+			tag = ""
+		}
+
+		if name, args, ok := strings.Cut(strings.TrimSpace(rule), "("); ok && strings.HasSuffix(args, ")") {
+			validator, err := parseRangeRule(name, strings.Split(args[:len(args)-1], ","), typ)
+			if err != nil {
+				return nil, err
+			}
+			validators = append(validators, validator)
+			continue
+		}
+
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(rule), "=")
+		if hasArg != (name == "min" || name == "max" || name == "minlen" || name == "maxlen" || name == "pattern") {
+			return nil, fmt.Errorf("invalid validation rule '%v'", rule)
+		}
+
+		var err error
+		switch name {
+		case "required":
+			validators = append(validators, required)
+		case "min", "max":
+			if kind := typ.Kind(); kind < reflect.Int || reflect.Float64 < kind {
+				return nil, fmt.Errorf("validation rule '%v' requires a number", name)
+			}
+			var f float64
+			if f, err = strconv.ParseFloat(arg, 64); err != nil {
+				return nil, fmt.Errorf("validation rule '%v': %w", name, err)
+			} else if name == "min" {
+				min = f
+			} else {
+				max = f
+			}
+		case "minlen", "maxlen":
+			if typ.Kind() != reflect.String {
+				return nil, fmt.Errorf("validation rule '%v' requires a string", name)
+			}
+			var n int
+			if n, err = strconv.Atoi(arg); err != nil || n < 0 {
+				return nil, fmt.Errorf("validation rule '%v': invalid length '%v'", name, arg)
+			} else if name == "minlen" {
+				minlen = n
+			} else {
+				maxlen = n
+			}
+		case "pattern":
+			if _, err = regexp.Compile(arg); err != nil {
+				return nil, fmt.Errorf("validation rule '%v': %w", name, err)
+			}
+			validators = append(validators, Pattern(arg, "invalid format"))
+		default:
+			validator, ok := namedValidators[name]
+			if !ok {
+				return nil, fmt.Errorf("unsupported validation rule '%v'", name)
+			}
+			validators = append(validators, validator())
+		}
+	}
+	if !math.IsNaN(min) || !math.IsNaN(max) {
+		validators = append(validators, NumRange(min, max))
+	}
+	if minlen != 0 || maxlen != -1 {
+		validators = append(validators, StrLength(minlen, maxlen))
+	}
+	return validators, nil
+}
+
+// ruleEnd returns the index of the comma that ends the first rule, skipping commas between parentheses, or -1 if there is none.
+func ruleEnd(tag string) int {
+	depth := 0
+	for i, c := range tag {
+		if c == '(' {
+			depth++
+		} else if c == ')' && 0 < depth {
+			depth--
+		} else if c == ',' && depth == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseRangeRule parses a range rule with its minimum and maximum for a field of the given type.
+func parseRangeRule(name string, args []string, typ reflect.Type) (Validator, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("validation rule '%v' requires a minimum and maximum", name)
+	}
+	min, max := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+	kind := typ.Kind()
+	switch name {
+	case "numrange":
+		if kind < reflect.Int || reflect.Float64 < kind {
+			return nil, fmt.Errorf("validation rule '%v' requires a number", name)
+		}
+		fmin, err1 := strconv.ParseFloat(min, 64)
+		fmax, err2 := strconv.ParseFloat(max, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("validation rule '%v': invalid range '%v,%v'", name, min, max)
+		}
+		return NumRange(fmin, fmax), nil
+	case "intrange":
+		if kind < reflect.Int || reflect.Uint64 < kind {
+			return nil, fmt.Errorf("validation rule '%v' requires an integer", name)
+		}
+		imin, err1 := strconv.ParseInt(min, 10, 64)
+		imax, err2 := strconv.ParseInt(max, 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("validation rule '%v': invalid range '%v,%v'", name, min, max)
+		}
+		return IntRange(imin, imax), nil
+	case "uintrange":
+		if kind < reflect.Uint || reflect.Uint64 < kind {
+			return nil, fmt.Errorf("validation rule '%v' requires an unsigned integer", name)
+		}
+		umin, err1 := strconv.ParseUint(min, 10, 64)
+		umax, err2 := strconv.ParseUint(max, 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("validation rule '%v': invalid range '%v,%v'", name, min, max)
+		}
+		return UintRange(umin, umax), nil
+	case "strlength":
+		if kind != reflect.String {
+			return nil, fmt.Errorf("validation rule '%v' requires a string", name)
+		}
+		nmin, err1 := strconv.Atoi(min)
+		nmax, err2 := strconv.Atoi(max)
+		if err1 != nil || err2 != nil || nmin < 0 || nmax < -1 {
+			return nil, fmt.Errorf("validation rule '%v': invalid range '%v,%v'", name, min, max)
+		}
+		return StrLength(nmin, nmax), nil
+	}
+	return nil, fmt.Errorf("unsupported validation rule '%v'", name)
+}
+
+// required matches if the input is not the zero value.
+func required(i any) error {
+	if i == nil || reflect.ValueOf(i).IsZero() {
+		return fmt.Errorf("required")
+	}
+	return nil
+}
+
+// PromptStruct prompts for each exported field of the struct pointed to by idst in order of declaration. The label is taken from the `prompt:"label"` struct tag or the field name, fields tagged `prompt:"-"` are skipped. The prompt tag may instead combine the other tags as `prompt:"label=Port,validate=numrange(1,65535),default=8080"`, where each value runs up to the next key. The `default:"value"` struct tag sets the initial value of zero fields, and the `options:"a,b,c"` struct tag turns the field into a list selection. The fields of nested structs are prompted under a heading. Validation rules are taken from the `validate:"..."` struct tag as a comma-separated list of: required, min=N, max=N, minlen=N, maxlen=N, pattern=REGEXP (must be last), numrange(MIN,MAX), intrange(MIN,MAX), uintrange(MIN,MAX), strlength(MIN,MAX), email, url, ip, ipv4, ipv6, hostname, domain, port, path, abspath, lowercase, and slug. Unsupported rules return an error before prompting.
+func PromptStruct(idst interface{}) error {
+	defer pauseProgress()()
+
+	fields, err := structFields(idst)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if field.section {
+			Printf(Bold, "%v\n", field.label)
+		} else if field.options != nil {
+			if err := Select(field.idst, field.label, field.options); err != nil {
+				return err
+			}
+		} else if err := Prompt(field.idst, field.label, field.validators...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormFromStruct returns a form with a field for each exported field of the struct pointed to by idst, with the same struct tags as PromptStruct. Fields with an `options:"a,b,c"` tag are select fields, boolean fields are yes or no prompts, and nested structs start a section with the struct field's label. Unsupported rules and invalid defaults return an error.
+func FormFromStruct(idst interface{}) (*Form, error) {
+	fields, err := structFields(idst)
+	if err != nil {
+		return nil, err
+	}
+	f := NewForm()
+	for _, field := range fields {
+		if field.section {
+			f.Print(field.label, "")
+		} else if field.options != nil {
+			f.Select(field.idst, field.label, field.options)
+		} else if dst, ok := field.idst.(*bool); ok && len(field.validators) == 0 {
+			f.YesNo(dst, field.label)
+		} else {
+			f.Prompt(field.idst, field.label, field.validators...)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,77 @@
+package prompt
+
+import (
+	"testing"
+)
+
+func TestFormFromStruct(t *testing.T) {
+	config := struct {
+		Host    string `prompt:"label=Host name,validate=required,hostname,default=localhost"`
+		Port    int    `prompt:"label=Port,validate=numrange(1,65535),default=8080"`
+		Workers uint   `prompt:"Workers" validate:"uintrange(1,64)" default:"4"`
+		Mode    string `prompt:"label=Mode,options=fast,safe"`
+		Verbose bool
+	}{}
+	f, err := FormFromStruct(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := []string{"Host name", "Port", "Workers", "Mode", "Verbose"}
+	if len(f.fields) != len(labels) {
+		t.Fatalf("%d fields, expected %d", len(f.fields), len(labels))
+	}
+	for i, label := range labels {
+		if f.fields[i].label != label {
+			t.Errorf("field %d: label %q, expected %q", i, f.fields[i].label, label)
+		}
+	}
+	if config.Host != "localhost" || config.Port != 8080 || config.Workers != 4 {
+		t.Errorf("defaults %v, %v, %v, expected localhost, 8080, 4", config.Host, config.Port, config.Workers)
+	}
+	if _, ok := f.fields[4].value.(*bool); !ok {
+		t.Errorf("boolean field has destination %T", f.fields[4].value)
+	}
+
+	fields, err := structFields(&config)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(fields[0].validators) != 2 || len(fields[1].validators) != 1 || len(fields[2].validators) != 1 {
+		t.Fatalf("validators %d, %d, %d, expected 2, 1, 1", len(fields[0].validators), len(fields[1].validators), len(fields[2].validators))
+	} else if err := fields[1].validators[0](0); err == nil {
+		t.Errorf("port 0 passes numrange(1,65535)")
+	} else if err := fields[1].validators[0](443); err != nil {
+		t.Errorf("port 443: %v", err)
+	}
+	if options := fields[3].options; len(options) != 2 || options[0] != "fast" || options[1] != "safe" {
+		t.Errorf("options %v, expected [fast safe]", options)
+	}
+}
+
+func TestFormFromStructErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  interface{}
+	}{
+		{"unknown", &struct {
+			A string `prompt:"label=A,validate=unknown"`
+		}{}},
+		{"range of string", &struct {
+			A string `prompt:"label=A,validate=numrange(1,2)"`
+		}{}},
+		{"range arguments", &struct {
+			A int `validate:"intrange(1)"`
+		}{}},
+		{"duplicate key", &struct {
+			A int `prompt:"label=A,label=B"`
+		}{}},
+		{"invalid default", &struct {
+			A int `prompt:"label=A,default=x"`
+		}{}},
+	}
+	for _, tt := range tests {
+		if _, err := FormFromStruct(tt.dst); err == nil {
+			t.Errorf("%v: expected an error", tt.name)
+		}
+	}
+}
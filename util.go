@@ -3,10 +3,162 @@ package prompt
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
+var outputMu sync.Mutex
+
+// progressBar is a progress bar that is paused by prompts and prints lines above itself.
+type progressBar interface {
+	Pause()
+	Resume()
+	printLine(msg string) // must be called with the output lock held
+}
+
+// activeProgress is the running progress bar, if any, and the number of prompts that paused it.
+var activeProgress struct {
+	sync.Mutex
+	bar    progressBar
+	pauses int
+}
+
+// setActiveProgress sets the running progress bar that is paused by prompts.
+func setActiveProgress(bar progressBar) {
+	activeProgress.Lock()
+	activeProgress.bar = bar
+	activeProgress.Unlock()
+}
+
+// unsetActiveProgress unsets the running progress bar if it is the given progress bar.
+func unsetActiveProgress(bar progressBar) {
+	activeProgress.Lock()
+	if activeProgress.bar == bar {
+		activeProgress.bar = nil
+	}
+	activeProgress.Unlock()
+}
+
+// currentProgress returns the running progress bar, or nil if there is none.
+func currentProgress() progressBar {
+	activeProgress.Lock()
+	defer activeProgress.Unlock()
+	return activeProgress.bar
+}
+
+// Lock locks the terminal output, so that prompts and progress bars do not draw concurrently with other output.
+func Lock() {
+	outputMu.Lock()
+}
+
+// Unlock unlocks the terminal output.
+func Unlock() {
+	outputMu.Unlock()
+}
+
+// SafePrintf prints a line while holding the output lock. If a progress bar is active the line is printed above it. All output other than prompts and progress bars should use SafePrintf when used concurrently.
+func SafePrintf(format string, args ...interface{}) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	msg := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	if bar := currentProgress(); bar != nil {
+		bar.printLine(msg)
+	} else {
+		fmt.Printf(escMoveStart+escClearLine+"%v\n", msg)
+	}
+}
+
+// pauseProgress pauses the active progress bar, if any, so that a prompt can be shown, and returns a function that resumes it. Nested prompts resume the progress bar only when the outermost prompt returns.
+func pauseProgress() func() {
+	activeProgress.Lock()
+	bar := activeProgress.bar
+	activeProgress.pauses++
+	first := activeProgress.pauses == 1
+	activeProgress.Unlock()
+	if bar != nil && first {
+		bar.Pause()
+	}
+	return func() {
+		activeProgress.Lock()
+		activeProgress.pauses--
+		last := activeProgress.pauses == 0
+		activeProgress.Unlock()
+		if bar != nil && last {
+			bar.Resume()
+		}
+	}
+}
+
+var scriptReader *bufio.Reader
+var scriptErr error // first error of a prompt that cannot return errors
+
+// SetScriptReader reads the answers of all prompts from r instead of the terminal, one answer per line. An empty line selects the default, and a line with only the escape character \x1B acts as pressing Escape, such as to go back to the previous field of a Form. Select reads the option or its index, and Checklist reads a comma-separated list of options or indices. Prompts return an error at the end of the script or when an answer is invalid, except for YesNo that returns the default and keeps the error for ScriptErr. Pass nil to read from the terminal again.
+func SetScriptReader(r io.Reader) {
+	scriptErr = nil
+	if r == nil {
+		scriptReader = nil
+	} else {
+		scriptReader = bufio.NewReader(r)
+	}
+}
+
+// ScriptErr returns the first error of a YesNo prompt reading from the script set by SetScriptReader, such as at the end of the script or for an invalid answer.
+func ScriptErr() error {
+	return scriptErr
+}
+
+func readScriptLine() (string, error) {
+	line, err := scriptReader.ReadString('\n')
+	if err != nil && line == "" {
+		if err == io.EOF {
+			return "", fmt.Errorf("script: no more answers")
+		}
+		return "", err
+	}
+	if line = strings.TrimRight(line, "\r\n"); line == "\x1B" {
+		return "", ErrEscape
+	}
+	return line, nil
+}
+
+// scriptChoice returns the index of the option matching the answer by its string or index.
+func scriptChoice(answer string, options []string) (int, error) {
+	answer = strings.TrimSpace(answer)
+	for i, option := range options {
+		if strings.TrimSpace(option) == answer {
+			return i, nil
+		}
+	}
+	if i, err := strconv.Atoi(answer); err == nil && 0 <= i && i < len(options) {
+		return i, nil
+	}
+	return 0, fmt.Errorf("script: unknown option '%v'", answer)
+}
+
+// statusMarker returns a check mark for success or a cross for failure, colored green or red respectively if color is true and the NO_COLOR environment variable is not set. In accessible mode words are used instead.
+func statusMarker(ok, color bool) string {
+	if accessible {
+		if ok {
+			return "done"
+		}
+		return "failed"
+	}
+	marker, style := "\u2713", Green
+	if !ok {
+		marker, style = "\u2717", Red
+	}
+	if color {
+		marker = style.sprintf("%v", marker)
+	}
+	return marker
+}
+
 func Min(a, b int) int {
 	if a < b {
 		return a
@@ -30,11 +182,233 @@ func Clip(x, a, b int) int {
 	return x
 }
 
+// runeWidth returns the number of terminal columns of a rune, which is zero for combining marks and two for wide East Asian characters.
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	} else if 0x1100 <= r && r <= 0x115F || 0x2E80 <= r && r <= 0xA4CF && r != 0x303F || 0xAC00 <= r && r <= 0xD7A3 || 0xF900 <= r && r <= 0xFAFF || 0xFE30 <= r && r <= 0xFE4F || 0xFF00 <= r && r <= 0xFF60 || 0xFFE0 <= r && r <= 0xFFE6 || 0x1F300 <= r && r <= 0x1F64F || 0x1F900 <= r && r <= 0x1F9FF || 0x20000 <= r && r <= 0x3FFFD {
+		return 2
+	}
+	return 1
+}
+
+// stringWidth returns the number of terminal columns of a string, where escape sequences such as those of a Style take up no columns.
+func stringWidth(s string) int {
+	n := 0
+	esc := 0 // 1 after the escape character, 2 within a control sequence
+	for _, r := range s {
+		if esc == 1 && r == '[' {
+			esc = 2
+		} else if esc == 2 {
+			if 0x40 <= r && r <= 0x7E {
+				esc = 0 // final byte
+			}
+		} else if r == '\x1B' {
+			esc = 1
+		} else {
+			esc = 0
+			n += runeWidth(r)
+		}
+	}
+	return n
+}
+
+// Style is a combination of text attributes used by Printf. The zero Style is plain text.
+type Style int
+
+const (
+	Bold Style = 1 << iota
+	Dim
+	Underline
+	Red
+	Green
+)
+
+// sprintf formats the arguments like fmt.Sprintf and wraps the result in the escape sequences of the style. Colors are left out if the NO_COLOR environment variable is set.
+func (style Style) sprintf(format string, args ...interface{}) string {
+	esc := ""
+	if style&Bold != 0 {
+		esc += escBold
+	}
+	if style&Dim != 0 {
+		esc += escDim
+	}
+	if style&Underline != 0 {
+		esc += escUnderline
+	}
+	if os.Getenv("NO_COLOR") == "" {
+		if style&Red != 0 {
+			esc += escRed
+		} else if style&Green != 0 {
+			esc += escGreen
+		}
+	}
+	if esc == "" {
+		return fmt.Sprintf(format, args...)
+	}
+	return esc + fmt.Sprintf(format, args...) + escReset
+}
+
+// Printf prints the formatted arguments like fmt.Printf in the given style.
+func Printf(style Style, format string, args ...interface{}) {
+	fmt.Print(style.sprintf(format, args...))
+}
+
+// Wrap breaks text into lines of at most width columns at spaces, and indents each line by the given number of spaces. A width of zero or less uses the terminal width. Words that are longer than the line are kept on their own line, and existing line breaks are kept.
+func Wrap(text string, width, indent int) string {
+	if width <= 0 {
+		width = 80
+		if _, cols, err := TerminalSize(); err == nil && 0 < cols {
+			width = cols
+		}
+	}
+	width = Max(1, width-indent)
+	padding := strings.Repeat(" ", indent)
+
+	sb := strings.Builder{}
+	for i, paragraph := range strings.Split(text, "\n") {
+		if i != 0 {
+			sb.WriteByte('\n')
+		}
+		n := 0
+		for _, word := range strings.Fields(paragraph) {
+			w := stringWidth(word)
+			if n == 0 {
+				sb.WriteString(padding)
+			} else if width < n+1+w {
+				sb.WriteString("\n" + padding)
+				n = 0
+			} else {
+				sb.WriteByte(' ')
+				n++
+			}
+			sb.WriteString(word)
+			n += w
+		}
+	}
+	return sb.String()
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur := Min(Min(row[j]+1, row[j-1]+1), prev+cost)
+			prev, row[j] = row[j], cur
+		}
+	}
+	return row[len(rb)]
+}
+
 func matchOption(query, option string) bool {
-	return strings.Contains(strings.ToLower(option), strings.ToLower(query))
+	return matchIndex([]rune(query), []rune(option)) != -1
 }
 
-func terminalList(label string, options []string, selected, maxLines, scrollOffset int, withQuery bool, exitEnter bool, optionMarkup func(int, int) string, keyPress func(rune, int)) error {
+// matchIndex returns the rune index of the first case-insensitive occurrence of query in option, or -1 if not found.
+func matchIndex(query, option []rune) int {
+Option:
+	for i := 0; i+len(query) <= len(option); i++ {
+		for j, r := range query {
+			if unicode.ToLower(option[i+j]) != unicode.ToLower(r) {
+				continue Option
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// highlightMatch underlines the characters of option that match the query.
+func highlightMatch(query []rune, option string) string {
+	if len(query) == 0 {
+		return option
+	}
+	rs := []rune(option)
+	i := matchIndex(query, rs)
+	if i == -1 {
+		return option
+	}
+	return string(rs[:i]) + escUnderline + string(rs[i:i+len(query)]) + escNoUnderline + string(rs[i+len(query):])
+}
+
+// accessibleList prints the numbered options on a single line and reads the chosen option numbers, which is used in accessible mode. It returns nil if no choice was entered.
+func accessibleList(label string, options []string, multiple bool) ([]int, error) {
+	items := make([]string, len(options))
+	for i, option := range options {
+		items[i] = fmt.Sprintf("%d) %v", i+1, option)
+	}
+	fmt.Printf("%v: %v\n", label, strings.Join(items, ", "))
+
+	input := bufio.NewReader(os.Stdin)
+	for {
+		if multiple {
+			fmt.Printf("Enter numbers separated by commas: ")
+		} else {
+			fmt.Printf("Enter a number: ")
+		}
+		line, err := input.ReadString('\n')
+		if err != nil && line == "" {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return nil, nil
+		}
+
+		choices := []int{}
+		valid := true
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || len(options) < n {
+				valid = false
+				break
+			}
+			choices = append(choices, n-1)
+		}
+		if valid && (multiple || len(choices) == 1) {
+			return choices, nil
+		}
+		fmt.Printf("Invalid choice, expected a number between 1 and %d\n", len(options))
+	}
+}
+
+// queryColumn returns the column of the caret after the label and the query of a prompt, starting at one.
+func queryColumn(label string, query []rune) int {
+	return stringWidth(label) + len(": ") + stringWidth(string(query)) + 1
+}
+
+// letterJump returns the selection after pressing a letter, which is the first option starting with the letter (case-insensitively). If the selected option already starts with the letter, it moves to the next such option and wraps around.
+func letterJump(options []string, optionsIndex []int, selected int, r rune) int {
+	r = unicode.ToLower(r)
+	startsWith := func(j int) bool {
+		first, _ := utf8.DecodeRuneInString(options[optionsIndex[j]])
+		return unicode.ToLower(first) == r
+	}
+
+	start := 0
+	if selected < len(optionsIndex) && startsWith(selected) {
+		start = selected + 1
+	}
+	for k := 0; k < len(optionsIndex); k++ {
+		if j := (start + k) % len(optionsIndex); startsWith(j) {
+			return j
+		}
+	}
+	return selected
+}
+
+func terminalList(label, header, footer string, options []string, selected, maxLines, scrollOffset int, withQuery bool, exitEnter bool, optionMarkup func(int, int) string, keyPress func(rune, int), selectAll func([]int)) error {
 	fmt.Printf("%v:", label)
 
 	padding := "  "
@@ -48,15 +422,26 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 		scrollOffset = (numLines - 1) / 2
 	}
 	windowStart := Clip(selected-(numLines-1)/2, 0, len(options)-numLines)
+	hdr := 0 // number of header rows
+	if header != "" {
+		hdr = 1
+		fmt.Printf("\n"+padding+"%v", Bold.sprintf("%v", header))
+	}
 	for i := 0; i < numLines; i++ {
 		fmt.Printf("\n"+padding+optionMarkup(windowStart+i, selected), options[windowStart+i])
 	}
+	listRows := numLines // number of rows of the options or the no options message
+	ftr := 0             // number of footer rows
+	if footer != "" {
+		ftr = 1
+		fmt.Printf("\n"+padding+"%v", Dim.sprintf("%v", footer))
+	}
 	// go to query
-	fmt.Printf(escMoveUpN+escMoveToCol, numLines, len(label)+3)
+	fmt.Printf(escMoveUpN+escMoveToCol, listRows+hdr+ftr, queryColumn(label, nil))
 	defer func() {
 		// go to bottom and clear output
-		fmt.Printf(escMoveStart + escClearLine + strings.Repeat(escMoveDown+escClearLine, numLines))
-		fmt.Printf(escMoveUpN, numLines)
+		fmt.Printf(escMoveStart + escClearLine + strings.Repeat(escMoveDown+escClearLine, listRows+hdr+ftr))
+		fmt.Printf(escMoveUpN, listRows+hdr+ftr)
 	}()
 
 	// option index in current view to option index in options
@@ -75,13 +460,14 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 	pos := 0 // position in query
 	var prevQuery, query []rune
 	prevSelected := selected
+	redraw := false // redraw all visible options
 
 	// read input
 	input := bufio.NewReader(os.Stdin)
 	for {
 		// change query results
 		if withQuery && string(query) != string(prevQuery) {
-			fmt.Printf(escMoveStart+escClearLine+"%v: %v"+escMoveToCol, label, string(query), len(label)+3+pos)
+			fmt.Printf(escMoveStart+escClearLine+"%v: %v"+escMoveToCol, label, string(query), queryColumn(label, query[:pos]))
 			i := 0
 			hasSelected := false
 			optionsIndex = optionsIndex[:0]
@@ -97,14 +483,19 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 			}
 			prevQuery = query
 
-			fmt.Printf(escMoveStart + strings.Repeat(escMoveDown+escClearLine, numLines))
-			if 0 < numLines {
-				fmt.Printf(escMoveUpN, numLines)
+			fmt.Printf(escMoveStart + strings.Repeat(escMoveDown, hdr) + strings.Repeat(escMoveDown+escClearLine, listRows+ftr))
+			if 0 < listRows+hdr+ftr {
+				fmt.Printf(escMoveUpN, listRows+hdr+ftr)
 			}
 			numLines = Min(maxLines, len(optionsIndex))
+			listRows = Max(1, numLines)
+			if footer != "" {
+				fmt.Printf(escMoveDownN+escMoveStart+padding+"%v", hdr+listRows+1, Dim.sprintf("%v", footer))
+				fmt.Printf(escMoveUpN+escMoveToCol, hdr+listRows+1, queryColumn(label, query[:pos]))
+			}
 			if numLines == 0 {
-				fmt.Printf("\n" + padding + escRed + "No options found" + escReset)
-				fmt.Printf(escMoveUp+escMoveToCol, len(label)+3+pos)
+				fmt.Printf(strings.Repeat(escMoveDown, hdr) + "\n" + padding + Red.sprintf("No options found"))
+				fmt.Printf(escMoveUpN+escMoveToCol, 1+hdr, queryColumn(label, query[:pos]))
 				prevSelected, selected = 0, 0
 			} else {
 				prevSelected = -1
@@ -115,7 +506,7 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 		}
 
 		// change selection and move window
-		if selected != prevSelected {
+		if selected != prevSelected || redraw {
 			prevWindowStart := windowStart
 			if prevSelected == -1 {
 				windowStart = Clip(selected-(numLines-1)/2, 0, len(optionsIndex)-numLines)
@@ -126,33 +517,35 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 				// move window down
 				windowStart = Min(selected+scrollOffset+1-numLines, len(optionsIndex)-numLines)
 			}
-			if windowStart != prevWindowStart || prevSelected == -1 {
+			if windowStart != prevWindowStart || prevSelected == -1 || redraw {
 				// print all options
+				fmt.Printf(strings.Repeat(escMoveDown, hdr))
 				for i := 0; i < numLines; i++ {
 					j := optionsIndex[windowStart+i]
-					fmt.Printf(escMoveDown+escMoveStart+escClearLine+padding+optionMarkup(j, optionsIndex[selected]), options[j])
+					fmt.Printf(escMoveDown+escMoveStart+escClearLine+padding+optionMarkup(j, optionsIndex[selected]), highlightMatch(query, options[j]))
 				}
 				// go to query
-				fmt.Printf(escMoveUpN+escMoveToCol, numLines, len(label)+3+pos)
+				fmt.Printf(escMoveUpN+escMoveToCol, numLines+hdr, queryColumn(label, query[:pos]))
 			} else {
 				jPrev, j := optionsIndex[prevSelected], optionsIndex[selected]
-				fmt.Printf(escMoveDownN+escMoveStart+escClearLine+padding+optionMarkup(jPrev, j), prevSelected-windowStart+1, options[jPrev])
+				fmt.Printf(escMoveDownN+escMoveStart+escClearLine+padding+optionMarkup(jPrev, j), prevSelected-windowStart+1+hdr, highlightMatch(query, options[jPrev]))
 				if selected < prevSelected {
 					fmt.Printf(escMoveUpN, prevSelected-selected)
 				} else {
 					fmt.Printf(escMoveDownN, selected-prevSelected)
 				}
 				j = optionsIndex[selected]
-				fmt.Printf(escMoveStart+escClearLine+padding+optionMarkup(j, j), options[j])
+				fmt.Printf(escMoveStart+escClearLine+padding+optionMarkup(j, j), highlightMatch(query, options[j]))
 				// go to query
-				fmt.Printf(escMoveUpN+escMoveToCol, selected-windowStart+1, len(label)+3+pos)
+				fmt.Printf(escMoveUpN+escMoveToCol, selected-windowStart+1+hdr, queryColumn(label, query[:pos]))
 			}
 			prevSelected = selected
+			redraw = false
 		} else if 0 < len(optionsIndex) {
 			j := optionsIndex[selected]
-			fmt.Printf(escMoveDownN+escMoveStart+escClearLine+padding+optionMarkup(j, j), selected-windowStart+1, options[j])
+			fmt.Printf(escMoveDownN+escMoveStart+escClearLine+padding+optionMarkup(j, j), selected-windowStart+1+hdr, highlightMatch(query, options[j]))
 			// go to query
-			fmt.Printf(escMoveUpN+escMoveToCol, selected-windowStart+1, len(label)+3+pos)
+			fmt.Printf(escMoveUpN+escMoveToCol, selected-windowStart+1+hdr, queryColumn(label, query[:pos]))
 		}
 
 		// read user input
@@ -162,7 +555,7 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 		}
 
 		if r == '\x03' { // interrupt
-			return keyInterrupt
+			return ErrInterrupt
 		} else if r == '\x04' || r == '\x26' { // Ctrl+D, Ctrl-Z
 			keyPress(r, optionsIndex[selected])
 			return nil
@@ -254,6 +647,9 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 			if len(optionsIndex) <= selected {
 				selected = 0
 			}
+		} else if r == '\x01' && selectAll != nil { // Ctrl+A - toggle all visible options
+			selectAll(optionsIndex)
+			redraw = true
 		} else if r == '\x01' { // Ctrl+A - move to start of line
 			fmt.Printf(strings.Repeat(escMoveLeft, pos))
 			pos = 0
@@ -276,6 +672,8 @@ func terminalList(label string, options []string, selected, maxLines, scrollOffs
 			fmt.Printf(strings.Repeat(escMoveLeft, len(query)))
 			query = query[pos:]
 			pos = 0
+		} else if len(query) == 0 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			selected = letterJump(options, optionsIndex, selected, r)
 		} else if withQuery && ' ' <= r {
 			query = append(query[:pos], append([]rune{r}, query[pos:]...)...)
 			fmt.Printf("%v"+strings.Repeat(escMoveLeft, len(query)-pos-1), string(query[pos:]))
@@ -0,0 +1,72 @@
+package prompt
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLetterJump(t *testing.T) {
+	options := []string{"Banana", "apple", "Cherry", "avocado", "blueberry", "Apricot"}
+	optionsIndex := []int{0, 1, 2, 3, 4, 5}
+
+	selected := 2
+	for j, expected := range []int{1, 3, 5, 1} {
+		if selected = letterJump(options, optionsIndex, selected, 'A'); selected != expected {
+			t.Fatalf("press %d: selected %d, expected %d", j, selected, expected)
+		}
+	}
+	if selected = letterJump(options, optionsIndex, selected, 'b'); selected != 0 {
+		t.Fatalf("selected %d, expected the first match 0", selected)
+	} else if selected = letterJump(options, optionsIndex, selected, 'b'); selected != 4 {
+		t.Fatalf("selected %d, expected the next match 4", selected)
+	} else if selected = letterJump(options, optionsIndex, selected, 'z'); selected != 4 {
+		t.Fatalf("selected %d, expected no change without a match", selected)
+	}
+
+	// filtered options are indexed by their position in the view
+	if selected = letterJump(options, []int{2, 4, 0}, 0, 'b'); selected != 1 {
+		t.Fatalf("selected %d, expected 1 in the filtered view", selected)
+	}
+}
+
+func TestQueryColumn(t *testing.T) {
+	tests := []struct {
+		label string
+		query string
+		col   int
+	}{
+		{"Name", "", 7},
+		{"Город", "", 8},
+		{"名前", "", 7},
+		{"Name", "ab", 9},
+		{"Город", "Мо", 10},
+		{"名前", "東京", 11},
+		{"名前 " + Dim.sprintf("(optional)"), "", 18},
+	}
+	for _, tt := range tests {
+		query := []rune(tt.query)
+		if col := queryColumn(tt.label, query); col != tt.col {
+			t.Errorf("%q: column %v, expected %v", tt.label, col, tt.col)
+		} else if line := fmt.Sprintf("%v: %v", tt.label, tt.query); stringWidth(line)+1 != col {
+			t.Errorf("%q: column %v does not follow the line %q", tt.label, col, line)
+		}
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+	}{
+		{"Name", 4},
+		{"Город", 5},
+		{"名前", 4},
+		{"  " + Bold.sprintf("Город"), 7},
+		{(Red | Bold).sprintf("名前") + "́", 4},
+	}
+	for _, tt := range tests {
+		if width := stringWidth(tt.s); width != tt.width {
+			t.Errorf("%q: width %v, expected %v", tt.s, width, tt.width)
+		}
+	}
+}
@@ -1,13 +1,28 @@
 package prompt
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math"
+	"mime"
+	"net"
+	"net/mail"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/text/language"
 )
 
 // Validator is a validator interface.
@@ -78,6 +93,97 @@ func NumRange(min, max float64) Validator {
 	}
 }
 
+// IntRange matches if the input is in the given integer range (inclusive), comparing without loss of precision. Use math.MinInt64 or math.MaxInt64 for an open limit.
+func IntRange(min, max int64) Validator {
+	return func(i any) error {
+		var num int64
+		switch v := i.(type) {
+		case int:
+			num = int64(v)
+		case int8:
+			num = int64(v)
+		case int16:
+			num = int64(v)
+		case int32:
+			num = int64(v)
+		case int64:
+			num = v
+		case uint, uint8, uint16, uint32, uint64:
+			u := reflect.ValueOf(v).Uint()
+			if math.MaxInt64 < u {
+				return fmt.Errorf("out of range [%v,%v]", min, max)
+			}
+			num = int64(u)
+		default:
+			if inter, ok := i.(interface{ Int64() int64 }); ok {
+				num = inter.Int64()
+			} else {
+				return fmt.Errorf("expected integer")
+			}
+		}
+		if num < min || max < num {
+			return fmt.Errorf("out of range [%v,%v]", min, max)
+		}
+		return nil
+	}
+}
+
+// UintRange matches if the input is in the given unsigned integer range (inclusive), comparing without loss of precision. Use math.MaxUint64 for an open limit.
+func UintRange(min, max uint64) Validator {
+	return func(i any) error {
+		var num uint64
+		switch v := i.(type) {
+		case uint:
+			num = uint64(v)
+		case uint8:
+			num = uint64(v)
+		case uint16:
+			num = uint64(v)
+		case uint32:
+			num = uint64(v)
+		case uint64:
+			num = v
+		case int, int8, int16, int32, int64:
+			n := reflect.ValueOf(v).Int()
+			if n < 0 {
+				return fmt.Errorf("out of range [%v,%v]", min, max)
+			}
+			num = uint64(n)
+		default:
+			if uinter, ok := i.(interface{ Uint64() uint64 }); ok {
+				num = uinter.Uint64()
+			} else {
+				return fmt.Errorf("expected integer")
+			}
+		}
+		if num < min || max < num {
+			return fmt.Errorf("out of range [%v,%v]", min, max)
+		}
+		return nil
+	}
+}
+
+// ComplexRange matches if the real and imaginary parts of the complex input are in the given ranges (inclusive). Use NaN or +/-Inf for an open limit.
+func ComplexRange(minReal, maxReal, minImag, maxImag float64) Validator {
+	return func(i any) error {
+		var num complex128
+		switch v := i.(type) {
+		case complex64:
+			num = complex128(v)
+		case complex128:
+			num = v
+		default:
+			return fmt.Errorf("expected complex number")
+		}
+		if re := real(num); !math.IsNaN(minReal) && re < minReal || !math.IsNaN(maxReal) && maxReal < re {
+			return fmt.Errorf("real part out of range [%v,%v]", minReal, maxReal)
+		} else if im := imag(num); !math.IsNaN(minImag) && im < minImag || !math.IsNaN(maxImag) && maxImag < im {
+			return fmt.Errorf("imaginary part out of range [%v,%v]", minImag, maxImag)
+		}
+		return nil
+	}
+}
+
 // DateRange matches if the input is in the given time range (inclusive). Use time.Time's zero value for an open limit.
 func DateRange(min, max time.Time) Validator {
 	return func(i any) error {
@@ -92,6 +198,44 @@ func DateRange(min, max time.Time) Validator {
 	}
 }
 
+// DurationRange matches if the input is in the given duration range (inclusive). Use a zero duration for an open limit.
+func DurationRange(min, max time.Duration) Validator {
+	return func(i any) error {
+		if d, ok := i.(time.Duration); ok {
+			if min != 0 && d < min || max != 0 && max < d {
+				return fmt.Errorf("out of range [%v,%v]", min, max)
+			}
+		} else {
+			return fmt.Errorf("expected duration")
+		}
+		return nil
+	}
+}
+
+// BeforeDuration matches if the input is a duration shorter than the given duration.
+func BeforeDuration(d time.Duration) Validator {
+	return func(i any) error {
+		if v, ok := i.(time.Duration); !ok {
+			return fmt.Errorf("expected duration")
+		} else if d <= v {
+			return fmt.Errorf("must be shorter than %v", d)
+		}
+		return nil
+	}
+}
+
+// AfterDuration matches if the input is a duration longer than the given duration.
+func AfterDuration(d time.Duration) Validator {
+	return func(i any) error {
+		if v, ok := i.(time.Duration); !ok {
+			return fmt.Errorf("expected duration")
+		} else if v <= d {
+			return fmt.Errorf("must be longer than %v", d)
+		}
+		return nil
+	}
+}
+
 // Prefix matches if the input has the given prefix.
 func Prefix(afix string) Validator {
 	return func(i any) error {
@@ -128,46 +272,87 @@ func Suffix(afix string) Validator {
 	}
 }
 
+// compareOrdered returns -1, 0, or +1 when i is less than, equal to, or greater than ref respectively. Both must be of the same integer, floating point, or timestamp type.
+func compareOrdered(i, ref any) (int, error) {
+	if reflect.TypeOf(ref) != reflect.TypeOf(i) {
+		return 0, fmt.Errorf("expected %v", describeType(ref))
+	}
+
+	cmp := func(less, greater bool) int {
+		if less {
+			return -1
+		} else if greater {
+			return 1
+		}
+		return 0
+	}
+	switch v := i.(type) {
+	case int:
+		return cmp(v < ref.(int), ref.(int) < v), nil
+	case int8:
+		return cmp(v < ref.(int8), ref.(int8) < v), nil
+	case int16:
+		return cmp(v < ref.(int16), ref.(int16) < v), nil
+	case int32:
+		return cmp(v < ref.(int32), ref.(int32) < v), nil
+	case int64:
+		return cmp(v < ref.(int64), ref.(int64) < v), nil
+	case uint:
+		return cmp(v < ref.(uint), ref.(uint) < v), nil
+	case uint8:
+		return cmp(v < ref.(uint8), ref.(uint8) < v), nil
+	case uint16:
+		return cmp(v < ref.(uint16), ref.(uint16) < v), nil
+	case uint32:
+		return cmp(v < ref.(uint32), ref.(uint32) < v), nil
+	case uint64:
+		return cmp(v < ref.(uint64), ref.(uint64) < v), nil
+	case float32:
+		return cmp(v < ref.(float32), ref.(float32) < v), nil
+	case float64:
+		return cmp(v < ref.(float64), ref.(float64) < v), nil
+	case time.Time:
+		return cmp(v.Before(ref.(time.Time)), v.After(ref.(time.Time))), nil
+	}
+	return 0, fmt.Errorf("expected integer, floating point, or timestamp")
+}
+
+// describeType returns a user-friendly description of the type of v.
+func describeType(v any) string {
+	switch v.(type) {
+	case int, int8, int16, int32, int64:
+		return "an integer"
+	case uint, uint8, uint16, uint32, uint64:
+		return "a positive integer"
+	case float32, float64:
+		return "a number"
+	case time.Time:
+		return "a date"
+	case string:
+		return "text"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
 // Before matches if the input is before the given number of date.
 func Before(before any) Validator {
 	return func(i any) error {
-		if reflect.TypeOf(before) != reflect.TypeOf(i) {
-			return fmt.Errorf("expected %v", reflect.TypeOf(before))
+		if cmp, err := compareOrdered(i, before); err != nil {
+			return err
+		} else if 0 <= cmp {
+			return fmt.Errorf("must be before %v", before)
 		}
+		return nil
+	}
+}
 
-		var ok bool
-		switch v := i.(type) {
-		case int:
-			ok = v < before.(int)
-		case int8:
-			ok = v < before.(int8)
-		case int16:
-			ok = v < before.(int16)
-		case int32:
-			ok = v < before.(int32)
-		case int64:
-			ok = v < before.(int64)
-		case uint:
-			ok = v < before.(uint)
-		case uint8:
-			ok = v < before.(uint8)
-		case uint16:
-			ok = v < before.(uint16)
-		case uint32:
-			ok = v < before.(uint32)
-		case uint64:
-			ok = v < before.(uint64)
-		case float32:
-			ok = v < before.(float32)
-		case float64:
-			ok = v < before.(float64)
-		case time.Time:
-			ok = v.Before(before.(time.Time))
-		default:
-			return fmt.Errorf("expected integer, floating point, or timestamp")
-		}
-		if !ok {
-			return fmt.Errorf("must be before %v", before)
+// NotAfter matches if the input is before or equal to the given number of date.
+func NotAfter(after any) Validator {
+	return func(i any) error {
+		if cmp, err := compareOrdered(i, after); err != nil {
+			return err
+		} else if 0 < cmp {
+			return fmt.Errorf("must not be after %v", after)
 		}
 		return nil
 	}
@@ -176,43 +361,22 @@ func Before(before any) Validator {
 // After matches if the input is after the given number of date.
 func After(after any) Validator {
 	return func(i any) error {
-		if reflect.TypeOf(after) != reflect.TypeOf(i) {
-			return fmt.Errorf("expected %v", reflect.TypeOf(after))
+		if cmp, err := compareOrdered(i, after); err != nil {
+			return err
+		} else if cmp <= 0 {
+			return fmt.Errorf("must be after %v", after)
 		}
+		return nil
+	}
+}
 
-		var ok bool
-		switch v := i.(type) {
-		case int:
-			ok = after.(int) < v
-		case int8:
-			ok = after.(int8) < v
-		case int16:
-			ok = after.(int16) < v
-		case int32:
-			ok = after.(int32) < v
-		case int64:
-			ok = after.(int64) < v
-		case uint:
-			ok = after.(uint) < v
-		case uint8:
-			ok = after.(uint8) < v
-		case uint16:
-			ok = after.(uint16) < v
-		case uint32:
-			ok = after.(uint32) < v
-		case uint64:
-			ok = after.(uint64) < v
-		case float32:
-			ok = after.(float32) < v
-		case float64:
-			ok = after.(float64) < v
-		case time.Time:
-			ok = v.After(after.(time.Time))
-		default:
-			return fmt.Errorf("expected integer, floating point, or timestamp")
-		}
-		if !ok {
-			return fmt.Errorf("must be after %v", after)
+// NotBefore matches if the input is after or equal to the given number of date.
+func NotBefore(before any) Validator {
+	return func(i any) error {
+		if cmp, err := compareOrdered(i, before); err != nil {
+			return err
+		} else if cmp < 0 {
+			return fmt.Errorf("must not be before %v", before)
 		}
 		return nil
 	}
@@ -237,11 +401,136 @@ func Pattern(pattern, message string) Validator {
 	}
 }
 
-// EmailAddress matches a valid e-mail address.
+// EmailAddress matches an e-mail address following RFC 5322, including quoted local parts and IP address literals as domain, such as "john doe"@[192.168.0.1]. Display names such as in John <john@example.com> are not allowed.
 func EmailAddress() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if _, err := mail.ParseAddress("<" + str + ">"); err != nil {
+			return fmt.Errorf("invalid e-mail address")
+		}
+		return nil
+	}
+}
+
+// EmailAddressSimple matches a common e-mail address of letters, digits, dots, and dashes, with a domain name.
+func EmailAddressSimple() Validator {
 	return Pattern(`^[\w\.-]+@([a-z0-9][a-z0-9-]{0,61}[a-z0-9]\.)+[a-z0-9]{2,63}$`, "invalid e-mail address")
 }
 
+// AbsoluteURL matches an absolute URL with a host and one of the given schemes, which are http and https by default.
+func AbsoluteURL(schemes ...string) Validator {
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		u, err := url.ParseRequestURI(str)
+		if err != nil || u.Scheme == "" {
+			return fmt.Errorf("invalid URL")
+		} else if u.Host == "" {
+			return fmt.Errorf("invalid URL: missing host")
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid URL: scheme must be %v", strings.Join(schemes, " or "))
+	}
+}
+
+// MIMEType matches a MIME type with optional parameters, such as application/json or text/html; charset=utf-8.
+func MIMEType() Validator {
+	return MIMETypeIn()
+}
+
+// MIMETypeIn matches a MIME type with optional parameters whose base type is one of the given types, or any base type if no types are given.
+func MIMETypeIn(types ...string) Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		mediaType, _, err := mime.ParseMediaType(str)
+		if err != nil {
+			return fmt.Errorf("invalid MIME type")
+		} else if typ, subtype, _ := strings.Cut(mediaType, "/"); typ == "" || subtype == "" {
+			return fmt.Errorf("invalid MIME type, expected type/subtype")
+		} else if len(types) == 0 {
+			return nil
+		}
+		for _, t := range types {
+			if strings.EqualFold(mediaType, t) {
+				return nil
+			}
+		}
+		return fmt.Errorf("unsupported MIME type %v, expected %v", mediaType, strings.Join(types, " or "))
+	}
+}
+
+// JWT matches a JSON Web Token of three base64url-encoded segments separated by dots, being the header, payload, and signature. The signature is not verified and may be empty for unsecured tokens.
+func JWT() Validator {
+	return JWTClaims()
+}
+
+// JWTClaims matches a JSON Web Token like JWT, whose payload is a JSON object that contains all given claims.
+func JWTClaims(required ...string) Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		segments := strings.Split(str, ".")
+		if len(segments) != 3 {
+			return fmt.Errorf("invalid JWT, expected three segments separated by dots")
+		}
+		var payload []byte
+		for j, name := range []string{"header", "payload", "signature"} {
+			b, err := base64.RawURLEncoding.DecodeString(segments[j])
+			if err != nil || len(b) == 0 && j != 2 {
+				return fmt.Errorf("invalid JWT %v", name)
+			} else if j == 1 {
+				payload = b
+			}
+		}
+		if len(required) == 0 {
+			return nil
+		}
+		claims := map[string]json.RawMessage{}
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			return fmt.Errorf("invalid JWT payload")
+		}
+		for _, claim := range required {
+			if _, ok := claims[claim]; !ok {
+				return fmt.Errorf("missing JWT claim '%v'", claim)
+			}
+		}
+		return nil
+	}
+}
+
 // TelephoneNumber matches a valid telephone number.
 func TelephoneNumber() Validator {
 	return Pattern(``, "invalid telephone number") // TODO
@@ -262,6 +551,28 @@ func IPv6Address() Validator {
 	return Pattern(`^(([a-fA-F0-9]{1,4}|):){1,7}([a-fA-F0-9]{1,4}|:)$`, "invalid IPv6 address")
 }
 
+var hostnameRegexp = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.?$`)
+
+// HostnameOrIP matches a hostname, such as localhost or sub.example.com, or an IPv4 or IPv6 address.
+func HostnameOrIP() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if net.ParseIP(str) != nil {
+			return nil
+		} else if str == "" || 253 < len(strings.TrimSuffix(str, ".")) || !hostnameRegexp.MatchString(str) {
+			return fmt.Errorf("invalid hostname or IP address")
+		}
+		return nil
+	}
+}
+
 // Port matches a valid port number.
 func Port() Validator {
 	return NumRange(1, 65535)
@@ -292,13 +603,8 @@ func DomainName() Validator {
 	return Pattern(`^([a-z0-9][a-z0-9-]{0,61}[a-z0-9]\.)+[a-z0-9]{2,63}$`, "invalid domain name")
 }
 
-// FQDN matches a fully qualified domain name.
-func FQDN() Validator {
-	return Pattern(`^([a-z0-9][a-z0-9-]{0,61}[a-z0-9]\.)+[a-z0-9]{2,63}\.$`, "invalid fully qualified domain name")
-}
-
-// Dir matches a path to an existing directory on the system.
-func Dir() Validator {
+// ValidTLD matches a domain name, or a top-level domain by itself, whose top-level domain exists, such as com or org, using the ICANN section of the public suffix list of golang.org/x/net/publicsuffix. Internationalized domain names may be given in Unicode or in Punycode.
+func ValidTLD() Validator {
 	return func(i any) error {
 		var str string
 		if s, ok := i.(string); ok {
@@ -308,17 +614,22 @@ func Dir() Validator {
 		} else {
 			return fmt.Errorf("expected string")
 		}
-		if info, err := os.Stat(str); err != nil {
-			return fmt.Errorf("file not found: %v", str)
-		} else if !info.Mode().IsDir() {
-			return fmt.Errorf("path is not regular file: %v", str)
+		str, err := idna.Lookup.ToASCII(strings.TrimSuffix(str, "."))
+		if err != nil {
+			return fmt.Errorf("invalid domain name")
+		}
+		tld := str[strings.LastIndexByte(str, '.')+1:]
+		if tld == "" {
+			return fmt.Errorf("missing top-level domain")
+		} else if _, icann := publicsuffix.PublicSuffix(tld); !icann {
+			return fmt.Errorf("unknown top-level domain '%v'", tld)
 		}
 		return nil
 	}
 }
 
-// File matches a path to an existing file on the system.
-func File() Validator {
+// PublicSuffix matches a domain name that is registered under a public suffix, such as example.com or example.co.uk, using golang.org/x/net/publicsuffix. Public suffixes by themselves, such as co.uk, do not match. Internationalized domain names may be given in Unicode or in Punycode.
+func PublicSuffix() Validator {
 	return func(i any) error {
 		var str string
 		if s, ok := i.(string); ok {
@@ -328,30 +639,670 @@ func File() Validator {
 		} else {
 			return fmt.Errorf("expected string")
 		}
-		if info, err := os.Stat(str); err != nil {
-			return fmt.Errorf("file not found: %v", str)
-		} else if !info.Mode().IsRegular() {
-			return fmt.Errorf("path is not regular file: %v", str)
+		str, err := idna.Lookup.ToASCII(strings.TrimSuffix(str, "."))
+		if err != nil || !hostnameRegexp.MatchString(str) {
+			return fmt.Errorf("invalid domain name")
+		}
+		// unlisted suffixes are the last label and are not managed by ICANN
+		suffix, icann := publicsuffix.PublicSuffix(str)
+		if !icann && !strings.Contains(suffix, ".") {
+			return fmt.Errorf("unknown public suffix '%v'", suffix)
+		} else if suffix == str {
+			return fmt.Errorf("'%v' is a public suffix", suffix)
 		}
 		return nil
 	}
 }
 
-// Is matches if the input matches the given value.
-func Is(elem any) Validator {
-	velem := reflect.ValueOf(elem)
+// FQDN matches a fully qualified domain name.
+func FQDN() Validator {
+	return Pattern(`^([a-z0-9][a-z0-9-]{0,61}[a-z0-9]\.)+[a-z0-9]{2,63}\.$`, "invalid fully qualified domain name")
+}
+
+// TimeZone matches an IANA time zone name, such as America/New_York, Europe/Paris, or UTC, using the time zone database of the system or of the time/tzdata package.
+func TimeZone() Validator {
 	return func(i any) error {
-		v := reflect.ValueOf(i)
-		if v.Type() != velem.Type() {
-			return fmt.Errorf("expected %v", velem.Type().Name())
-		} else if !velem.Equal(v) {
-			return fmt.Errorf("expected '%v'", elem)
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if str == "" || str == "Local" {
+			return fmt.Errorf("unknown timezone")
+		} else if _, err := time.LoadLocation(str); err != nil {
+			return fmt.Errorf("unknown timezone")
 		}
 		return nil
 	}
 }
 
-// In matches if the input matches any element of the list.
+// iso639_1 are the two-letter language codes of ISO 639-1.
+var iso639_1 = []string{
+	"aa", "ab", "ae", "af", "ak", "am", "an", "ar", "as", "av", "ay", "az", "ba", "be", "bg", "bi",
+	"bm", "bn", "bo", "br", "bs", "ca", "ce", "ch", "co", "cr", "cs", "cu", "cv", "cy", "da", "de",
+	"dv", "dz", "ee", "el", "en", "eo", "es", "et", "eu", "fa", "ff", "fi", "fj", "fo", "fr", "fy",
+	"ga", "gd", "gl", "gn", "gu", "gv", "ha", "he", "hi", "ho", "hr", "ht", "hu", "hy", "hz", "ia",
+	"id", "ie", "ig", "ii", "ik", "io", "is", "it", "iu", "ja", "jv", "ka", "kg", "ki", "kj", "kk",
+	"kl", "km", "kn", "ko", "kr", "ks", "ku", "kv", "kw", "ky", "la", "lb", "lg", "li", "ln", "lo",
+	"lt", "lu", "lv", "mg", "mh", "mi", "mk", "ml", "mn", "mr", "ms", "mt", "my", "na", "nb", "nd",
+	"ne", "ng", "nl", "nn", "no", "nr", "nv", "ny", "oc", "oj", "om", "or", "os", "pa", "pi", "pl",
+	"ps", "pt", "qu", "rm", "rn", "ro", "ru", "rw", "sa", "sc", "sd", "se", "sg", "si", "sk", "sl",
+	"sm", "sn", "so", "sq", "sr", "ss", "st", "su", "sv", "sw", "ta", "te", "tg", "th", "ti", "tk",
+	"tl", "tn", "to", "tr", "ts", "tt", "tw", "ty", "ug", "uk", "ur", "uz", "ve", "vi", "vo", "wa",
+	"wo", "xh", "yi", "yo", "za", "zh", "zu",
+}
+
+// ISO639_1 matches a two-letter language code of ISO 639-1, such as en or zh.
+func ISO639_1() Validator {
+	return In(iso639_1)
+}
+
+// BCP47 matches a BCP 47 language tag with registered subtags, such as en-US, zh-Hant, or sr-Latn-RS, using golang.org/x/text/language.
+func BCP47() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if _, err := language.Parse(str); err != nil {
+			if verr, ok := err.(interface{ Subtag() string }); ok {
+				return fmt.Errorf("invalid language tag, unknown subtag '%v'", verr.Subtag())
+			}
+			return fmt.Errorf("invalid language tag")
+		}
+		return nil
+	}
+}
+
+// iso4217 are the active three-letter currency codes of ISO 4217, including funds and precious metals.
+var iso4217 = []string{
+	"AED", "AFN", "ALL", "AMD", "AOA", "ARS", "AUD", "AWG", "AZN", "BAM", "BBD", "BDT", "BGN", "BHD",
+	"BIF", "BMD", "BND", "BOB", "BOV", "BRL", "BSD", "BTN", "BWP", "BYN", "BZD", "CAD", "CDF", "CHE",
+	"CHF", "CHW", "CLF", "CLP", "CNY", "COP", "COU", "CRC", "CUP", "CVE", "CZK", "DJF", "DKK", "DOP",
+	"DZD", "EGP", "ERN", "ETB", "EUR", "FJD", "FKP", "GBP", "GEL", "GHS", "GIP", "GMD", "GNF", "GTQ",
+	"GYD", "HKD", "HNL", "HTG", "HUF", "IDR", "ILS", "INR", "IQD", "IRR", "ISK", "JMD", "JOD", "JPY",
+	"KES", "KGS", "KHR", "KMF", "KPW", "KRW", "KWD", "KYD", "KZT", "LAK", "LBP", "LKR", "LRD", "LSL",
+	"LYD", "MAD", "MDL", "MGA", "MKD", "MMK", "MNT", "MOP", "MRU", "MUR", "MVR", "MWK", "MXN", "MXV",
+	"MYR", "MZN", "NAD", "NGN", "NIO", "NOK", "NPR", "NZD", "OMR", "PAB", "PEN", "PGK", "PHP", "PKR",
+	"PLN", "PYG", "QAR", "RON", "RSD", "RUB", "RWF", "SAR", "SBD", "SCR", "SDG", "SEK", "SGD", "SHP",
+	"SLE", "SOS", "SRD", "SSP", "STN", "SVC", "SYP", "SZL", "THB", "TJS", "TMT", "TND", "TOP", "TRY",
+	"TTD", "TWD", "TZS", "UAH", "UGX", "USD", "USN", "UYI", "UYU", "UYW", "UZS", "VED", "VES", "VND",
+	"VUV", "WST", "XAF", "XAG", "XAU", "XBA", "XBB", "XBC", "XBD", "XCD", "XCG", "XDR", "XOF", "XPD",
+	"XPF", "XPT", "XSU", "XTS", "XUA", "XXX", "YER", "ZAR", "ZMW", "ZWG",
+}
+
+// CurrencyCode matches an active three-letter currency code of ISO 4217, such as USD, EUR, or JPY.
+func CurrencyCode() Validator {
+	return In(iso4217)
+}
+
+// iso3166Alpha2 are the two-letter country codes of ISO 3166-1.
+var iso3166Alpha2 = []string{
+	"AD", "AE", "AF", "AG", "AI", "AL", "AM", "AO", "AQ", "AR", "AS", "AT", "AU", "AW", "AX", "AZ",
+	"BA", "BB", "BD", "BE", "BF", "BG", "BH", "BI", "BJ", "BL", "BM", "BN", "BO", "BQ", "BR", "BS",
+	"BT", "BV", "BW", "BY", "BZ", "CA", "CC", "CD", "CF", "CG", "CH", "CI", "CK", "CL", "CM", "CN",
+	"CO", "CR", "CU", "CV", "CW", "CX", "CY", "CZ", "DE", "DJ", "DK", "DM", "DO", "DZ", "EC", "EE",
+	"EG", "EH", "ER", "ES", "ET", "FI", "FJ", "FK", "FM", "FO", "FR", "GA", "GB", "GD", "GE", "GF",
+	"GG", "GH", "GI", "GL", "GM", "GN", "GP", "GQ", "GR", "GS", "GT", "GU", "GW", "GY", "HK", "HM",
+	"HN", "HR", "HT", "HU", "ID", "IE", "IL", "IM", "IN", "IO", "IQ", "IR", "IS", "IT", "JE", "JM",
+	"JO", "JP", "KE", "KG", "KH", "KI", "KM", "KN", "KP", "KR", "KW", "KY", "KZ", "LA", "LB", "LC",
+	"LI", "LK", "LR", "LS", "LT", "LU", "LV", "LY", "MA", "MC", "MD", "ME", "MF", "MG", "MH", "MK",
+	"ML", "MM", "MN", "MO", "MP", "MQ", "MR", "MS", "MT", "MU", "MV", "MW", "MX", "MY", "MZ", "NA",
+	"NC", "NE", "NF", "NG", "NI", "NL", "NO", "NP", "NR", "NU", "NZ", "OM", "PA", "PE", "PF", "PG",
+	"PH", "PK", "PL", "PM", "PN", "PR", "PS", "PT", "PW", "PY", "QA", "RE", "RO", "RS", "RU", "RW",
+	"SA", "SB", "SC", "SD", "SE", "SG", "SH", "SI", "SJ", "SK", "SL", "SM", "SN", "SO", "SR", "SS",
+	"ST", "SV", "SX", "SY", "SZ", "TC", "TD", "TF", "TG", "TH", "TJ", "TK", "TL", "TM", "TN", "TO",
+	"TR", "TT", "TV", "TW", "TZ", "UA", "UG", "UM", "US", "UY", "UZ", "VA", "VC", "VE", "VG", "VI",
+	"VN", "VU", "WF", "WS", "YE", "YT", "ZA", "ZM", "ZW",
+}
+
+// iso3166Alpha3 are the three-letter country codes of ISO 3166-1.
+var iso3166Alpha3 = []string{
+	"ABW", "AFG", "AGO", "AIA", "ALA", "ALB", "AND", "ARE", "ARG", "ARM", "ASM", "ATA", "ATF", "ATG",
+	"AUS", "AUT", "AZE", "BDI", "BEL", "BEN", "BES", "BFA", "BGD", "BGR", "BHR", "BHS", "BIH", "BLM",
+	"BLR", "BLZ", "BMU", "BOL", "BRA", "BRB", "BRN", "BTN", "BVT", "BWA", "CAF", "CAN", "CCK", "CHE",
+	"CHL", "CHN", "CIV", "CMR", "COD", "COG", "COK", "COL", "COM", "CPV", "CRI", "CUB", "CUW", "CXR",
+	"CYM", "CYP", "CZE", "DEU", "DJI", "DMA", "DNK", "DOM", "DZA", "ECU", "EGY", "ERI", "ESH", "ESP",
+	"EST", "ETH", "FIN", "FJI", "FLK", "FRA", "FRO", "FSM", "GAB", "GBR", "GEO", "GGY", "GHA", "GIB",
+	"GIN", "GLP", "GMB", "GNB", "GNQ", "GRC", "GRD", "GRL", "GTM", "GUF", "GUM", "GUY", "HKG", "HMD",
+	"HND", "HRV", "HTI", "HUN", "IDN", "IMN", "IND", "IOT", "IRL", "IRN", "IRQ", "ISL", "ISR", "ITA",
+	"JAM", "JEY", "JOR", "JPN", "KAZ", "KEN", "KGZ", "KHM", "KIR", "KNA", "KOR", "KWT", "LAO", "LBN",
+	"LBR", "LBY", "LCA", "LIE", "LKA", "LSO", "LTU", "LUX", "LVA", "MAC", "MAF", "MAR", "MCO", "MDA",
+	"MDG", "MDV", "MEX", "MHL", "MKD", "MLI", "MLT", "MMR", "MNE", "MNG", "MNP", "MOZ", "MRT", "MSR",
+	"MTQ", "MUS", "MWI", "MYS", "MYT", "NAM", "NCL", "NER", "NFK", "NGA", "NIC", "NIU", "NLD", "NOR",
+	"NPL", "NRU", "NZL", "OMN", "PAK", "PAN", "PCN", "PER", "PHL", "PLW", "PNG", "POL", "PRI", "PRK",
+	"PRT", "PRY", "PSE", "PYF", "QAT", "REU", "ROU", "RUS", "RWA", "SAU", "SDN", "SEN", "SGP", "SGS",
+	"SHN", "SJM", "SLB", "SLE", "SLV", "SMR", "SOM", "SPM", "SRB", "SSD", "STP", "SUR", "SVK", "SVN",
+	"SWE", "SWZ", "SXM", "SYC", "SYR", "TCA", "TCD", "TGO", "THA", "TJK", "TKL", "TKM", "TLS", "TON",
+	"TTO", "TUN", "TUR", "TUV", "TWN", "TZA", "UGA", "UKR", "UMI", "URY", "USA", "UZB", "VAT", "VCT",
+	"VEN", "VGB", "VIR", "VNM", "VUT", "WLF", "WSM", "YEM", "ZAF", "ZMB", "ZWE",
+}
+
+// CountryCode matches a two-letter country code of ISO 3166-1, such as US, GB, or DE.
+func CountryCode() Validator {
+	return In(iso3166Alpha2)
+}
+
+// CountryCodeAlpha3 matches a three-letter country code of ISO 3166-1, such as USA, GBR, or DEU.
+func CountryCodeAlpha3() Validator {
+	return In(iso3166Alpha3)
+}
+
+// ibanLengths are the lengths of the IBANs per country code as listed in the IBAN registry of SWIFT.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22,
+	"BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27,
+	"DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27,
+	"GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HN": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20, "LB": 28,
+	"LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22,
+	"MK": 19, "MN": 20, "MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23,
+	"PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24,
+	"SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// IBAN matches an International Bank Account Number, such as GB82 WEST 1234 5698 7654 32, with a known country code, the length for that country, and valid check digits. Spaces are ignored.
+func IBAN() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+
+		iban := []byte{}
+		for pos, r := range []rune(str) {
+			if 'a' <= r && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			if '0' <= r && r <= '9' || 'A' <= r && r <= 'Z' {
+				iban = append(iban, byte(r))
+			} else if r != ' ' {
+				return fmt.Errorf("invalid character '%c' at position %d", r, pos+1)
+			}
+		}
+		if len(iban) < 4 {
+			return fmt.Errorf("too short")
+		}
+		n, ok := ibanLengths[string(iban[:2])]
+		if !ok {
+			return fmt.Errorf("unknown country code %s", iban[:2])
+		} else if len(iban) != n {
+			return fmt.Errorf("invalid length for %s, expected %d characters", iban[:2], n)
+		} else if iban[2] < '0' || '9' < iban[2] || iban[3] < '0' || '9' < iban[3] {
+			return fmt.Errorf("invalid check digits")
+		}
+
+		// move the country code and check digits to the end and convert letters to 10 to 35
+		mod := 0
+		for _, c := range append(iban[4:], iban[:4]...) {
+			if c <= '9' {
+				mod = (mod*10 + int(c-'0')) % 97
+			} else {
+				mod = (mod*100 + int(c-'A'+10)) % 97
+			}
+		}
+		if mod != 1 {
+			return fmt.Errorf("invalid checksum")
+		}
+		return nil
+	}
+}
+
+// Lowercase matches if the input has no uppercase letters.
+func Lowercase() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		for pos, r := range []rune(str) {
+			if unicode.IsUpper(r) {
+				return fmt.Errorf("uppercase character '%c' at position %d", r, pos+1)
+			}
+		}
+		return nil
+	}
+}
+
+// Slug matches a URL slug of lowercase alphanumerics separated by single dashes, such as my-page-2.
+func Slug() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if str == "" {
+			return fmt.Errorf("empty slug")
+		}
+		return checkChars([]rune(str), func(r rune) bool {
+			return 'a' <= r && r <= 'z' || '0' <= r && r <= '9'
+		}, "lowercase letter or digit", true)
+	}
+}
+
+// DNSLabel matches a DNS label as in RFC 1123 of at most 63 lowercase alphanumerics and dashes, starting with a letter and not ending with a dash, as used for resource names.
+func DNSLabel() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		rs := []rune(str)
+		if len(rs) == 0 {
+			return fmt.Errorf("empty name")
+		} else if 63 < len(rs) {
+			return fmt.Errorf("too long, maximum is 63")
+		} else if rs[0] < 'a' || 'z' < rs[0] {
+			return fmt.Errorf("invalid character '%c' at position 1, expected lowercase letter", rs[0])
+		}
+		return checkChars(rs, func(r rune) bool {
+			return 'a' <= r && r <= 'z' || '0' <= r && r <= '9'
+		}, "lowercase letter, digit, or dash", false)
+	}
+}
+
+// S3BucketName matches an AWS S3 bucket name of 3 to 63 lowercase alphanumerics and single dashes, starting and ending with a letter or digit, that is not formatted as an IP address.
+func S3BucketName() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		rs := []rune(str)
+		if len(rs) < 3 {
+			return fmt.Errorf("too short, minimum is 3")
+		} else if 63 < len(rs) {
+			return fmt.Errorf("too long, maximum is 63")
+		} else if net.ParseIP(str) != nil {
+			return fmt.Errorf("must not be an IP address")
+		}
+		return checkChars(rs, func(r rune) bool {
+			return 'a' <= r && r <= 'z' || '0' <= r && r <= '9'
+		}, "lowercase letter, digit, or dash", true)
+	}
+}
+
+// awsRegions are the AWS region codes, including the GovCloud and China regions.
+var awsRegions = []string{
+	"af-south-1",
+	"ap-east-1", "ap-east-2",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-south-1", "ap-south-2",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4", "ap-southeast-5", "ap-southeast-7",
+	"ca-central-1", "ca-west-1",
+	"cn-north-1", "cn-northwest-1",
+	"eu-central-1", "eu-central-2",
+	"eu-north-1",
+	"eu-south-1", "eu-south-2",
+	"eu-west-1", "eu-west-2", "eu-west-3",
+	"il-central-1",
+	"me-central-1", "me-south-1",
+	"mx-central-1",
+	"sa-east-1",
+	"us-east-1", "us-east-2",
+	"us-gov-east-1", "us-gov-west-1",
+	"us-west-1", "us-west-2",
+}
+
+// AWSRegion matches an AWS region code, such as us-east-1.
+func AWSRegion() Validator {
+	return In(awsRegions)
+}
+
+// AWSAccountID matches an AWS account ID of exactly 12 digits.
+func AWSAccountID() Validator {
+	return Pattern(`^[0-9]{12}$`, "invalid AWS account ID, expected 12 digits")
+}
+
+// Identifier matches a Go or C-style identifier of letters, digits, and underscores, not starting with a digit.
+func Identifier() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if str == "" {
+			return fmt.Errorf("empty identifier")
+		}
+		for pos, r := range []rune(str) {
+			if pos == 0 && '0' <= r && r <= '9' {
+				return fmt.Errorf("invalid character '%c' at position 1, expected letter or underscore", r)
+			} else if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_') {
+				return fmt.Errorf("invalid character '%c' at position %d, expected letter, digit, or underscore", r, pos+1)
+			}
+		}
+		return nil
+	}
+}
+
+// EnvVarValue matches a value that can be set as an environment variable, i.e. without null bytes.
+func EnvVarValue() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if pos := strings.IndexByte(str, 0); pos != -1 {
+			return fmt.Errorf("invalid null byte at position %d", utf8.RuneCountInString(str[:pos])+1)
+		}
+		return nil
+	}
+}
+
+// ShellSafe matches a non-empty string that can be used in a shell command without quoting, i.e. letters, digits, and any of -_./ only.
+func ShellSafe() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if str == "" {
+			return fmt.Errorf("empty string")
+		}
+		for pos, r := range []rune(str) {
+			if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-' || r == '_' || r == '.' || r == '/') {
+				return fmt.Errorf("invalid character '%c' at position %d, expected letter, digit, or any of -_./", r, pos+1)
+			}
+		}
+		return nil
+	}
+}
+
+// passwordStrengths are the labels of the password strength scores.
+var passwordStrengths = []string{"very weak", "weak", "fair", "good", "strong"}
+
+// PasswordStrength matches a password whose strength score is at least minScore between 0 and 4. The score is one point for each of: at least 8 characters, an uppercase letter, a lowercase letter, a digit, and a special character, with a maximum of 4. The error lists what is missing.
+func PasswordStrength(minScore int) Validator {
+	return passwordStrength(minScore, false)
+}
+
+// PasswordStrengthMessage is like PasswordStrength but includes the strength label in the error, such as "password is too weak: fair".
+func PasswordStrengthMessage(minScore int) Validator {
+	return passwordStrength(minScore, true)
+}
+
+func passwordStrength(minScore int, label bool) Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		var upper, lower, digit, special bool
+		for _, r := range str {
+			if unicode.IsUpper(r) {
+				upper = true
+			} else if unicode.IsLower(r) {
+				lower = true
+			} else if unicode.IsDigit(r) {
+				digit = true
+			} else if !unicode.IsSpace(r) {
+				special = true
+			}
+		}
+
+		missing := []string{}
+		for _, rule := range []struct {
+			ok   bool
+			name string
+		}{
+			{8 <= utf8.RuneCountInString(str), "at least 8 characters"},
+			{upper, "an uppercase letter"},
+			{lower, "a lowercase letter"},
+			{digit, "a digit"},
+			{special, "a special character"},
+		} {
+			if !rule.ok {
+				missing = append(missing, rule.name)
+			}
+		}
+		score := Min(5-len(missing), 4)
+		if minScore <= score {
+			return nil
+		}
+		msg := "password is too weak"
+		if label {
+			msg += ": " + passwordStrengths[score]
+		}
+		return fmt.Errorf("%v, add %v", msg, strings.Join(missing, ", "))
+	}
+}
+
+// LuhnCheck matches a number whose digits satisfy the Luhn algorithm, such as credit card numbers. Spaces and dashes are ignored.
+func LuhnCheck() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		_, err := luhnDigits(str)
+		return err
+	}
+}
+
+// CreditCard matches a credit card number of 13 to 19 digits that satisfies the Luhn algorithm and starts with the issuer prefix of a major card network, such as Visa, Mastercard, American Express, or Discover. Spaces and dashes are ignored.
+func CreditCard() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		digits, err := luhnDigits(str)
+		if err != nil {
+			return err
+		} else if len(digits) < 13 || 19 < len(digits) {
+			return fmt.Errorf("invalid credit card number, expected 13 to 19 digits")
+		} else if digits[0] < '2' || '6' < digits[0] {
+			return fmt.Errorf("invalid credit card number, unknown card issuer")
+		}
+		return nil
+	}
+}
+
+// luhnDigits returns the digits without spaces and dashes, and an error if there are other characters or if the checksum fails.
+func luhnDigits(str string) ([]byte, error) {
+	digits := []byte{}
+	for pos, r := range []rune(str) {
+		if '0' <= r && r <= '9' {
+			digits = append(digits, byte(r))
+		} else if r != ' ' && r != '-' {
+			return nil, fmt.Errorf("invalid character '%c' at position %d, expected digit", r, pos+1)
+		}
+	}
+	if len(digits) < 2 {
+		return nil, fmt.Errorf("too short")
+	}
+
+	sum := 0
+	for j := range digits {
+		d := int(digits[len(digits)-1-j] - '0')
+		if j%2 == 1 {
+			if d *= 2; 9 < d {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	if sum%10 != 0 {
+		return nil, fmt.Errorf("invalid checksum")
+	}
+	return digits, nil
+}
+
+// HexString matches a non-empty string of hexadecimal characters.
+func HexString() Validator {
+	return hexString(-1)
+}
+
+// HexStringLength matches a string of hexadecimal characters that encodes exactly n bytes, which is 2n characters.
+func HexStringLength(n int) Validator {
+	return hexString(n)
+}
+
+// SHA1 matches a SHA-1 digest of 40 hexadecimal characters.
+func SHA1() Validator {
+	return hexString(20)
+}
+
+// SHA256 matches a SHA-256 digest of 64 hexadecimal characters.
+func SHA256() Validator {
+	return hexString(32)
+}
+
+// SHA512 matches a SHA-512 digest of 128 hexadecimal characters.
+func SHA512() Validator {
+	return hexString(64)
+}
+
+func hexString(n int) Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if str == "" {
+			return fmt.Errorf("empty hexadecimal string")
+		}
+		for pos, r := range []rune(str) {
+			if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F') {
+				return fmt.Errorf("invalid character '%c' at position %d, expected hexadecimal digit", r, pos+1)
+			}
+		}
+		if 0 <= n && len(str) != 2*n {
+			return fmt.Errorf("expected %d hexadecimal characters (%d bytes), got %d", 2*n, n, len(str))
+		}
+		return nil
+	}
+}
+
+// checkChars checks that all characters are valid or single dashes that separate valid characters. If strict, dashes may not be at the start or end.
+func checkChars(rs []rune, valid func(rune) bool, expected string, strict bool) error {
+	for pos, r := range rs {
+		if r == '-' {
+			if strict && pos == 0 {
+				return fmt.Errorf("dash at start")
+			} else if pos == len(rs)-1 {
+				return fmt.Errorf("dash at end")
+			} else if strict && rs[pos-1] == '-' {
+				return fmt.Errorf("consecutive dashes at position %d", pos+1)
+			}
+		} else if !valid(r) {
+			return fmt.Errorf("invalid character '%c' at position %d, expected %v", r, pos+1, expected)
+		}
+	}
+	return nil
+}
+
+// Dir matches a path to an existing directory on the system.
+func Dir() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if info, err := os.Stat(str); err != nil {
+			return fmt.Errorf("file not found: %v", str)
+		} else if !info.Mode().IsDir() {
+			return fmt.Errorf("path is not regular file: %v", str)
+		}
+		return nil
+	}
+}
+
+// File matches a path to an existing file on the system.
+func File() Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		if info, err := os.Stat(str); err != nil {
+			return fmt.Errorf("file not found: %v", str)
+		} else if !info.Mode().IsRegular() {
+			return fmt.Errorf("path is not regular file: %v", str)
+		}
+		return nil
+	}
+}
+
+// Is matches if the input matches the given value.
+func Is(elem any) Validator {
+	velem := reflect.ValueOf(elem)
+	return func(i any) error {
+		v := reflect.ValueOf(i)
+		if v.Type() != velem.Type() {
+			return fmt.Errorf("expected %v", velem.Type().Name())
+		} else if !equalValue(velem, v) {
+			return fmt.Errorf("expected '%v'", elem)
+		}
+		return nil
+	}
+}
+
+// In matches if the input matches any element of the list.
 func In(list any) Validator {
 	vlist := reflect.ValueOf(list)
 	if vlist.Kind() != reflect.Slice {
@@ -364,14 +1315,125 @@ func In(list any) Validator {
 			return fmt.Errorf("expected %v", elemType.Name())
 		}
 		for j := 0; j < vlist.Len(); j++ {
-			if vlist.Index(j).Equal(v) {
+			if equalValue(vlist.Index(j), v) {
 				return nil
 			}
 		}
+		if str, ok := i.(string); ok {
+			if candidates := suggestions(str, vlist, false); 0 < len(candidates) {
+				return &Suggest{fmt.Errorf("not available"), candidates}
+			}
+		}
 		return fmt.Errorf("not available")
 	}
 }
 
+// InFold matches if the input matches any element of the list under Unicode case-folding.
+func InFold(list []string) Validator {
+	return func(i any) error {
+		var str string
+		if s, ok := i.(string); ok {
+			str = s
+		} else if stringer, ok := i.(interface{ String() string }); ok {
+			str = stringer.String()
+		} else {
+			return fmt.Errorf("expected string")
+		}
+		for _, elem := range list {
+			if strings.EqualFold(elem, str) {
+				return nil
+			}
+		}
+		err := fmt.Errorf("expected one of %v", listValues(reflect.ValueOf(list)))
+		if candidates := suggestions(str, reflect.ValueOf(list), true); 0 < len(candidates) {
+			return &Suggest{err, candidates}
+		}
+		return err
+	}
+}
+
+// Suggest is a validation error with candidate corrections for the input.
+type Suggest struct {
+	Err        error
+	Candidates []string
+}
+
+func (err *Suggest) Error() string {
+	return err.Err.Error()
+}
+
+func (err *Suggest) Unwrap() error {
+	return err.Err
+}
+
+var suggestMax = 3 // maximum number of suggestions
+
+// suggestions returns the string elements of the list that are close to str by Levenshtein distance, closest first.
+func suggestions(str string, vlist reflect.Value, fold bool) []string {
+	if vlist.Type().Elem().Kind() != reflect.String {
+		return nil
+	} else if fold {
+		str = strings.ToLower(str)
+	}
+
+	maxDist := Max(1, Min(3, len([]rune(str))/3))
+	candidates := []string{}
+	dists := []int{}
+	for j := 0; j < vlist.Len(); j++ {
+		elem := vlist.Index(j).String()
+		cmp := elem
+		if fold {
+			cmp = strings.ToLower(elem)
+		}
+		if dist := levenshtein(str, cmp); dist <= maxDist {
+			k := len(dists)
+			for 0 < k && dist < dists[k-1] {
+				k--
+			}
+			dists = append(dists[:k], append([]int{dist}, dists[k:]...)...)
+			candidates = append(candidates[:k], append([]string{elem}, candidates[k:]...)...)
+		}
+	}
+	if suggestMax < len(candidates) {
+		candidates = candidates[:suggestMax]
+	}
+	return candidates
+}
+
+// InFunc matches if the input matches any element of the list using the given equality function.
+func InFunc(list any, eq func(a, b any) bool) Validator {
+	vlist := reflect.ValueOf(list)
+	if vlist.Kind() != reflect.Slice {
+		panic("list must be a slice")
+	}
+	return func(i any) error {
+		for j := 0; j < vlist.Len(); j++ {
+			if eq(i, vlist.Index(j).Interface()) {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of %v", listValues(vlist))
+	}
+}
+
+var listValuesMax = 5 // maximum number of values listed in error messages
+
+// listValues returns a truncated list of values for use in error messages.
+func listValues(vlist reflect.Value) string {
+	sb := strings.Builder{}
+	for j := 0; j < vlist.Len(); j++ {
+		if j != 0 {
+			sb.WriteString(", ")
+		}
+		if j == listValuesMax {
+			sb.WriteString("...")
+			break
+		}
+		fmt.Fprintf(&sb, "'%v'", vlist.Index(j).Interface())
+	}
+	return sb.String()
+}
+
 // NotIn matches if the input does not match any element of the list.
 func NotIn(list any) Validator {
 	vlist := reflect.ValueOf(list)
@@ -385,7 +1447,7 @@ func NotIn(list any) Validator {
 			return fmt.Errorf("expected %v", elemType.Name())
 		}
 		for j := 0; j < vlist.Len(); j++ {
-			if vlist.Index(j).Equal(v) {
+			if equalValue(vlist.Index(j), v) {
 				return fmt.Errorf("not available")
 			}
 		}
@@ -432,3 +1494,157 @@ func Or(validators ...Validator) Validator {
 		return fmt.Errorf("not available")
 	}
 }
+
+// XOR evaluates multiple named validators using the logical XOR operator, i.e. exactly one validator must be satisfied.
+func XOR(validators ...NamedValidator) Validator {
+	return func(i any) error {
+		matched := matchValidators(validators, i)
+		if len(matched) == 0 {
+			names := make([]string, len(validators))
+			for j, val := range validators {
+				names[j] = val.Name
+			}
+			return fmt.Errorf("must match one of %v", joinNames(names, "or"))
+		} else if 1 < len(matched) {
+			return fmt.Errorf("matched %v", joinNames(matched, "and"))
+		}
+		return nil
+	}
+}
+
+// AtMostOne evaluates multiple named validators and is satisfied when at most one validator is satisfied.
+func AtMostOne(validators ...NamedValidator) Validator {
+	return func(i any) error {
+		if matched := matchValidators(validators, i); 1 < len(matched) {
+			return fmt.Errorf("matched %v", joinNames(matched, "and"))
+		}
+		return nil
+	}
+}
+
+// NamedValidator is a validator with a name, which is used in the error messages of XOR and AtMostOne.
+type NamedValidator struct {
+	Name      string
+	Validator Validator
+}
+
+// Named gives a validator a name for XOR and AtMostOne.
+func Named(name string, validator Validator) NamedValidator {
+	return NamedValidator{name, validator}
+}
+
+// matchValidators returns the names of the satisfied validators.
+func matchValidators(validators []NamedValidator, i any) []string {
+	matched := []string{}
+	for _, val := range validators {
+		if err := val.Validator(i); err == nil {
+			matched = append(matched, val.Name)
+		}
+	}
+	return matched
+}
+
+func joinNames(names []string, conj string) string {
+	if len(names) == 1 {
+		return "'" + names[0] + "'"
+	} else if len(names) == 2 {
+		if conj == "and" {
+			return "both '" + names[0] + "' and '" + names[1] + "'"
+		}
+		return "either '" + names[0] + "' or '" + names[1] + "'"
+	}
+	return "'" + strings.Join(names[:len(names)-1], "', '") + "', " + conj + " '" + names[len(names)-1] + "'"
+}
+
+// AsyncValidator is an option with a validator that may take a long time to complete, such as one that makes network calls. Prompt runs asynchronous validators after all validators are satisfied while showing a spinner next to the answer. The validation can be cancelled with Ctrl+C, which returns ErrInterrupt, and fails when it does not complete within the timeout. A timeout of zero means no timeout.
+func AsyncValidator(validator func(context.Context, any) error, timeout time.Duration) PromptOption {
+	return func(o *promptOptions) {
+		o.async = append(o.async, func(i any) error {
+			return runAsyncValidator(validator, timeout, i)
+		})
+	}
+}
+
+// WithDateFormat makes Prompt parse time.Time input with the given layout of the time package, such as "2006-01-02", instead of guessing the format. The layout is shown as a hint when the input is empty, and the default value is formatted with it.
+func WithDateFormat(layout string) PromptOption {
+	return func(o *promptOptions) {
+		o.layout = layout
+	}
+}
+
+// Sensitive makes Prompt mask the default value, the input, and the answer with asterisks, while the default value remains editable, such as for an existing API token. Form fields with this option are redacted.
+func Sensitive() PromptOption {
+	return func(o *promptOptions) {
+		o.sensitive = true
+	}
+}
+
+func runAsyncValidator(validator func(context.Context, any) error, timeout time.Duration, i any) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if 0 < timeout {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- validator(ctx, i)
+	}()
+	done := func(err error) error {
+		if err != nil && ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("validation timed out after %v", timeout)
+		}
+		return err
+	}
+
+	if _, _, err := terminalSize(os.Stdin.Fd()); scriptReader != nil || accessible || err != nil {
+		// no spinner and no Ctrl+C when not interactive
+		select {
+		case err := <-result:
+			return done(err)
+		case <-ctx.Done():
+			return fmt.Errorf("validation timed out after %v", timeout)
+		}
+	}
+
+	// make raw and hide input, reads return every 100ms to allow stopping
+	restore, err := makeRawTerminal(true, 0, 1)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	var wg sync.WaitGroup
+	var stop atomic.Bool
+	interrupt := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b := make([]byte, 1)
+		for !stop.Load() {
+			if n, _ := os.Stdin.Read(b); n == 1 && b[0] == '\x03' {
+				close(interrupt)
+				return
+			}
+		}
+	}()
+	defer wg.Wait()
+	defer stop.Store(true)
+
+	frames := []rune{'|', '/', '-', '\\'}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	defer fmt.Printf(" " + escMoveLeft)
+	for frame := 0; ; frame++ {
+		fmt.Printf("%c"+escMoveLeft, frames[frame%len(frames)])
+		select {
+		case err := <-result:
+			return done(err)
+		case <-interrupt:
+			return ErrInterrupt
+		case <-ctx.Done():
+			return fmt.Errorf("validation timed out after %v", timeout)
+		case <-ticker.C:
+		}
+	}
+}
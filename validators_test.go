@@ -0,0 +1,149 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsyncValidatorNonInteractive(t *testing.T) {
+	failed := errors.New("failed")
+	opts := applyOptions([]PromptOption{
+		AsyncValidator(func(ctx context.Context, i any) error {
+			if i.(string) == "" {
+				return failed
+			}
+			return nil
+		}, time.Second),
+		AsyncValidator(func(ctx context.Context, i any) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, 10*time.Millisecond),
+	})
+	if len(opts.async) != 2 {
+		t.Fatalf("%d async validators, expected 2", len(opts.async))
+	}
+
+	validator, slow := opts.async[0], opts.async[1]
+	if err := validator("a"); err != nil {
+		t.Fatal(err)
+	} else if err := validator(""); err != failed {
+		t.Fatalf("error %v, expected %v", err, failed)
+	}
+	if err := slow("a"); err == nil || err.Error() != "validation timed out after 10ms" {
+		t.Fatalf("error %v, expected a timeout", err)
+	}
+}
+
+func TestXOR(t *testing.T) {
+	short := Named("short", StrLength(0, 3))
+	digits := Named("digits", Pattern(`^[0-9]+$`, "expected digits"))
+	tests := []struct {
+		validator Validator
+		s         string
+		err       string
+	}{
+		{XOR(short, digits), "ab", ""},
+		{XOR(short, digits), "12345", ""},
+		{XOR(short, digits), "123", "matched both 'short' and 'digits'"},
+		{XOR(short, digits), "abcde", "must match one of either 'short' or 'digits'"},
+		{AtMostOne(short, digits), "abcde", ""},
+		{AtMostOne(short, digits), "123", "matched both 'short' and 'digits'"},
+	}
+	for _, tt := range tests {
+		if err := tt.validator(tt.s); err == nil && tt.err != "" || err != nil && err.Error() != tt.err {
+			t.Errorf("%q: error %v, expected %q", tt.s, err, tt.err)
+		}
+	}
+}
+
+func TestIntRange(t *testing.T) {
+	tests := []struct {
+		validator Validator
+		i         any
+		valid     bool
+	}{
+		{IntRange(0, math.MaxInt64), int64(math.MaxInt64), true},
+		{IntRange(0, math.MaxInt64-1), int64(math.MaxInt64), false},
+		{IntRange(math.MinInt64, 0), int64(math.MinInt64), true},
+		{IntRange(math.MinInt64+1, 0), int64(math.MinInt64), false},
+		{IntRange(0, math.MaxInt64), uint64(math.MaxInt64), true},
+		{IntRange(0, math.MaxInt64), uint64(math.MaxInt64 + 1), false},
+		{IntRange(1<<53, 1<<53), int64(1<<53 + 1), false},
+		{IntRange(-5, 5), int8(-5), true},
+		{IntRange(-5, 5), 6, false},
+		{IntRange(0, 1), 0.5, false},
+		{UintRange(0, math.MaxUint64), uint64(math.MaxUint64), true},
+		{UintRange(0, math.MaxUint64-1), uint64(math.MaxUint64), false},
+		{UintRange(1<<53, 1<<53), uint64(1<<53 + 1), false},
+		{UintRange(1, 10), uint8(1), true},
+		{UintRange(1, 10), uint(0), false},
+		{UintRange(0, 10), -1, false},
+	}
+	for j, tt := range tests {
+		if err := tt.validator(tt.i); (err == nil) != tt.valid {
+			t.Errorf("test %d: %T(%v) gives error %v, expected valid %v", j, tt.i, tt.i, err, tt.valid)
+		}
+	}
+}
+
+func TestInclusiveBounds(t *testing.T) {
+	now := time.Now()
+	values := []any{
+		int(7), int8(7), int16(7), int32(7), int64(7),
+		uint(7), uint8(7), uint16(7), uint32(7), uint64(7),
+		float32(7), float64(7), now,
+	}
+	for _, v := range values {
+		if err := NotAfter(v)(v); err != nil {
+			t.Errorf("NotAfter(%T): %v", v, err)
+		}
+		if err := NotBefore(v)(v); err != nil {
+			t.Errorf("NotBefore(%T): %v", v, err)
+		}
+		if err := Before(v)(v); err == nil {
+			t.Errorf("Before(%T) matches an equal value", v)
+		}
+		if err := After(v)(v); err == nil {
+			t.Errorf("After(%T) matches an equal value", v)
+		}
+	}
+
+	if err := NotAfter(now)(now.Add(time.Second)); err == nil {
+		t.Errorf("NotAfter matches a later date")
+	} else if err := NotBefore(now)(now.Add(-time.Second)); err == nil {
+		t.Errorf("NotBefore matches an earlier date")
+	} else if err := Before(now)(7); err == nil || err.Error() != "expected a date" {
+		t.Errorf("error %v, expected %q", err, "expected a date")
+	}
+}
+
+func TestS3BucketName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"my-bucket", true},
+		{"abc", true},
+		{"a1.b2", false},
+		{"ab", false},
+		{strings.Repeat("a", 63), true},
+		{strings.Repeat("a", 64), false},
+		{"my--bucket", false},
+		{"-bucket", false},
+		{"bucket-", false},
+		{"A-BUCKET", false},
+		{"my_bucket", false},
+		{"192.168.1.1", false},
+		{"192-168-1-1", true},
+		{"bücket", false},
+	}
+	for _, tt := range tests {
+		if err := S3BucketName()(tt.name); (err == nil) != tt.valid {
+			t.Errorf("%q: error %v, expected valid %v", tt.name, err, tt.valid)
+		}
+	}
+}